@@ -3,13 +3,11 @@ module github.com/constellation39/framework
 go 1.24.0
 
 require (
-	github.com/lestrrat-go/file-rotatelogs v2.4.0+incompatible
 	go.uber.org/zap v1.27.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	github.com/jonboulle/clockwork v0.5.0 // indirect
-	github.com/lestrrat-go/strftime v1.1.1 // indirect
-	github.com/pkg/errors v0.9.1 // indirect
+	github.com/stretchr/testify v1.10.0 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
 )
@@ -0,0 +1,271 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// 异步队列满时的处理策略
+const (
+	OverflowBlock      = "block"       // 阻塞等待队列腾出空间
+	OverflowDropNewest = "drop_newest" // 丢弃新日志
+	OverflowDropOldest = "drop_oldest" // 丢弃队列中最旧的日志
+	OverflowDropBelow  = "drop_below"  // 丢弃低于 AsyncDropLevel 的新日志，其余阻塞等待
+)
+
+const defaultAsyncQueueSize = 8192
+
+// asyncItem 队列中的一条日志
+type asyncItem struct {
+	core   zapcore.Core // 带有 With 字段的下游 core
+	ent    zapcore.Entry
+	fields []zapcore.Field
+}
+
+// asyncQueue 有界环形队列，由单个后台协程消费
+type asyncQueue struct {
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	idle     *sync.Cond
+
+	buf    []asyncItem
+	head   int
+	count  int
+	busy   bool // 后台协程正在写出一批日志
+	closed bool
+
+	policy    string
+	dropLevel zapcore.Level
+	dropped   atomic.Uint64
+
+	errOut zapcore.WriteSyncer // 后台写入失败时的错误输出，与 zap 的默认 ErrorOutput 相同
+	done   chan struct{}
+}
+
+func newAsyncQueue(size int, policy string, dropLevel zapcore.Level) *asyncQueue {
+	if size <= 0 {
+		size = defaultAsyncQueueSize
+	}
+	q := &asyncQueue{
+		buf:       make([]asyncItem, size),
+		policy:    policy,
+		dropLevel: dropLevel,
+		errOut:    zapcore.Lock(os.Stderr),
+		done:      make(chan struct{}),
+	}
+	q.notEmpty = sync.NewCond(&q.mu)
+	q.notFull = sync.NewCond(&q.mu)
+	q.idle = sync.NewCond(&q.mu)
+
+	go q.run()
+	return q
+}
+
+// parseOverflowPolicy 校验队列满时的处理策略
+func parseOverflowPolicy(policy string) (string, error) {
+	switch p := strings.ToLower(policy); p {
+	case "":
+		return OverflowBlock, nil
+	case OverflowBlock, OverflowDropNewest, OverflowDropOldest, OverflowDropBelow:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown async overflow policy: %q", policy)
+	}
+}
+
+// push 入队，队列满时按策略处理
+func (q *asyncQueue) push(item asyncItem) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for q.count == len(q.buf) && !q.closed {
+		switch q.policy {
+		case OverflowDropNewest:
+			q.dropped.Add(1)
+			return
+		case OverflowDropOldest:
+			q.buf[q.head] = asyncItem{}
+			q.head = (q.head + 1) % len(q.buf)
+			q.count--
+			q.dropped.Add(1)
+		case OverflowDropBelow:
+			if item.ent.Level < q.dropLevel {
+				q.dropped.Add(1)
+				return
+			}
+			q.notFull.Wait()
+		default:
+			q.notFull.Wait()
+		}
+	}
+
+	if q.closed {
+		q.dropped.Add(1)
+		return
+	}
+
+	q.buf[(q.head+q.count)%len(q.buf)] = item
+	q.count++
+	q.notEmpty.Signal()
+}
+
+// run 后台协程：每次取出队列中的全部日志批量写出
+func (q *asyncQueue) run() {
+	defer close(q.done)
+
+	batch := make([]asyncItem, 0, len(q.buf))
+	for {
+		q.mu.Lock()
+		for q.count == 0 && !q.closed {
+			q.idle.Broadcast()
+			q.notEmpty.Wait()
+		}
+		if q.count == 0 && q.closed {
+			q.idle.Broadcast()
+			q.mu.Unlock()
+			return
+		}
+
+		for q.count > 0 {
+			batch = append(batch, q.buf[q.head])
+			q.buf[q.head] = asyncItem{}
+			q.head = (q.head + 1) % len(q.buf)
+			q.count--
+		}
+		q.busy = true
+		q.notFull.Broadcast()
+		q.mu.Unlock()
+
+		for i := range batch {
+			if err := writeChecked(batch[i].core, batch[i].ent, batch[i].fields); err != nil {
+				q.reportError(batch[i].ent, err)
+			}
+			batch[i] = asyncItem{}
+		}
+		batch = batch[:0]
+
+		q.mu.Lock()
+		q.busy = false
+		q.mu.Unlock()
+	}
+}
+
+// reportError 输出后台写入的错误，格式与 zap 的 CheckedEntry 相同
+func (q *asyncQueue) reportError(ent zapcore.Entry, err error) {
+	fmt.Fprintf(q.errOut, "%v write error: %v\n", ent.Time, err)
+	_ = q.errOut.Sync()
+}
+
+// flush 等待队列中已有的日志全部写出
+func (q *asyncQueue) flush() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for q.count > 0 || q.busy {
+		q.idle.Wait()
+	}
+}
+
+// Close 停止接收新日志，写完队列中剩余日志后返回
+func (q *asyncQueue) Close() error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil
+	}
+	q.closed = true
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+	q.mu.Unlock()
+
+	<-q.done
+	return nil
+}
+
+// writeChecked 经下游 Check 后再写入，保证各输出自身的级别区间生效。
+// 返回下游 core 的写入错误，由上层 CheckedEntry 输出到 ErrorOutput
+func writeChecked(core zapcore.Core, ent zapcore.Entry, fields []zapcore.Field) error {
+	return writeEntry(core.Check(ent, nil), fields)
+}
+
+// writeEntry 写入已检查的日志并返回写入错误，ce 为 nil 时不写入
+func writeEntry(ce *zapcore.CheckedEntry, fields []zapcore.Field) error {
+	if ce == nil {
+		return nil
+	}
+
+	// CheckedEntry 只将写入错误输出到 ErrorOutput，借此取回错误
+	var errs writeErrors
+	ce.ErrorOutput = &errs
+	ce.Write(fields...)
+	return errors.Join(errs...)
+}
+
+// writeErrors 作为 CheckedEntry.ErrorOutput，收集写入错误
+type writeErrors []error
+
+func (e *writeErrors) Write(p []byte) (int, error) {
+	// 格式为 "<时间> write error: <错误>"
+	msg := strings.TrimSuffix(string(p), "\n")
+	if _, after, ok := strings.Cut(msg, " write error: "); ok {
+		msg = after
+	}
+	*e = append(*e, errors.New(msg))
+	return len(p), nil
+}
+
+func (e *writeErrors) Sync() error {
+	return nil
+}
+
+// asyncCore 将写入放入队列，由后台协程写到下游 core。
+// 字段在后台协程中编码，被记录的对象在记录后不应再被修改
+type asyncCore struct {
+	zapcore.Core
+	queue *asyncQueue
+}
+
+func (c *asyncCore) With(fields []zapcore.Field) zapcore.Core {
+	return &asyncCore{
+		Core:  c.Core.With(fields),
+		queue: c.queue,
+	}
+}
+
+func (c *asyncCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *asyncCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	// 复制字段切片，调用方可能复用
+	copied := make([]zapcore.Field, len(fields))
+	copy(copied, fields)
+
+	c.queue.push(asyncItem{core: c.Core, ent: ent, fields: copied})
+
+	// DPanic 及以上级别可能紧接着 panic 或退出进程，立即写出
+	if ent.Level > zapcore.ErrorLevel {
+		return c.Sync()
+	}
+	return nil
+}
+
+func (c *asyncCore) Sync() error {
+	c.queue.flush()
+	return c.Core.Sync()
+}
+
+// DroppedEntries 返回异步队列因溢出而丢弃的日志条数（含重新加载前的队列），未启用异步时为 0
+func (l *Logger) DroppedEntries() uint64 {
+	return l.sinks.dropped()
+}
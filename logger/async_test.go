@@ -0,0 +1,272 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// blockingCore 记录写入的消息，release 关闭前每次写入都会阻塞
+type blockingCore struct {
+	mu      sync.Mutex
+	msgs    []string
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingCore() *blockingCore {
+	return &blockingCore{started: make(chan struct{}, 64), release: make(chan struct{})}
+}
+
+func (c *blockingCore) Enabled(zapcore.Level) bool        { return true }
+func (c *blockingCore) With([]zapcore.Field) zapcore.Core { return c }
+func (c *blockingCore) Sync() error                       { return nil }
+func (c *blockingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+func (c *blockingCore) Write(ent zapcore.Entry, _ []zapcore.Field) error {
+	c.mu.Lock()
+	c.msgs = append(c.msgs, ent.Message)
+	c.mu.Unlock()
+	c.started <- struct{}{}
+	<-c.release
+	return nil
+}
+
+func (c *blockingCore) written() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return strings.Join(c.msgs, ",")
+}
+
+// waitStarted 等待下游开始写入一条日志
+func (c *blockingCore) waitStarted(t *testing.T) {
+	t.Helper()
+	select {
+	case <-c.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("downstream write not started")
+	}
+}
+
+func pushMsg(q *asyncQueue, core zapcore.Core, lvl zapcore.Level, msg string) {
+	q.push(asyncItem{core: core, ent: zapcore.Entry{Level: lvl, Message: msg}})
+}
+
+// fullQueue 创建容量为 2 的队列：第一条日志阻塞在下游，随后两条填满队列
+func fullQueue(t *testing.T, policy string) (*asyncQueue, *blockingCore) {
+	t.Helper()
+	core := newBlockingCore()
+	q := newAsyncQueue(2, policy, zapcore.WarnLevel)
+	t.Cleanup(func() { _ = q.Close() })
+
+	pushMsg(q, core, zapcore.InfoLevel, "1")
+	core.waitStarted(t)
+	pushMsg(q, core, zapcore.InfoLevel, "2")
+	pushMsg(q, core, zapcore.InfoLevel, "3")
+	return q, core
+}
+
+func TestAsyncDropNewest(t *testing.T) {
+	q, core := fullQueue(t, OverflowDropNewest)
+
+	pushMsg(q, core, zapcore.ErrorLevel, "4")
+	close(core.release)
+	_ = q.Close()
+
+	if got := core.written(); got != "1,2,3" {
+		t.Errorf("written = %s, want 1,2,3", got)
+	}
+	if n := q.dropped.Load(); n != 1 {
+		t.Errorf("dropped = %d, want 1", n)
+	}
+}
+
+func TestAsyncDropOldest(t *testing.T) {
+	q, core := fullQueue(t, OverflowDropOldest)
+
+	pushMsg(q, core, zapcore.InfoLevel, "4")
+	pushMsg(q, core, zapcore.InfoLevel, "5")
+	close(core.release)
+	_ = q.Close()
+
+	if got := core.written(); got != "1,4,5" {
+		t.Errorf("written = %s, want 1,4,5", got)
+	}
+	if n := q.dropped.Load(); n != 2 {
+		t.Errorf("dropped = %d, want 2", n)
+	}
+}
+
+func TestAsyncDropBelow(t *testing.T) {
+	q, core := fullQueue(t, OverflowDropBelow)
+
+	// 低于 warn 的日志直接丢弃
+	pushMsg(q, core, zapcore.InfoLevel, "info")
+	if n := q.dropped.Load(); n != 1 {
+		t.Errorf("dropped = %d, want 1", n)
+	}
+
+	// 其余日志阻塞等待
+	pushed := make(chan struct{})
+	go func() {
+		pushMsg(q, core, zapcore.WarnLevel, "warn")
+		close(pushed)
+	}()
+	select {
+	case <-pushed:
+		t.Fatal("warn entry did not wait for space")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(core.release)
+	<-pushed
+	_ = q.Close()
+
+	if got := core.written(); got != "1,2,3,warn" {
+		t.Errorf("written = %s, want 1,2,3,warn", got)
+	}
+	if n := q.dropped.Load(); n != 1 {
+		t.Errorf("dropped = %d, want 1", n)
+	}
+}
+
+func TestAsyncBlock(t *testing.T) {
+	q, core := fullQueue(t, OverflowBlock)
+
+	pushed := make(chan struct{})
+	go func() {
+		pushMsg(q, core, zapcore.DebugLevel, "4")
+		close(pushed)
+	}()
+	select {
+	case <-pushed:
+		t.Fatal("push did not block on a full queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(core.release)
+	<-pushed
+	_ = q.Close()
+
+	if got := core.written(); got != "1,2,3,4" {
+		t.Errorf("written = %s, want 1,2,3,4", got)
+	}
+	if n := q.dropped.Load(); n != 0 {
+		t.Errorf("dropped = %d, want 0", n)
+	}
+}
+
+func TestAsyncPushAfterClose(t *testing.T) {
+	core := newBlockingCore()
+	close(core.release)
+	q := newAsyncQueue(4, OverflowBlock, zapcore.WarnLevel)
+	_ = q.Close()
+
+	pushMsg(q, core, zapcore.InfoLevel, "late")
+	if got := core.written(); got != "" {
+		t.Errorf("written = %s, want nothing", got)
+	}
+	if n := q.dropped.Load(); n != 1 {
+		t.Errorf("dropped = %d, want 1", n)
+	}
+}
+
+func TestAsyncDroppedEntriesReported(t *testing.T) {
+	core := newBlockingCore()
+	l, err := New(
+		WithFile(false, "", ""),
+		WithConsole(false, false),
+		WithCore(core),
+		WithAsync(true, 1, OverflowDropNewest, ""),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	l.Info("1")
+	core.waitStarted(t)
+	l.Info("2")
+	l.Info("3")
+	l.Info("4")
+
+	if n := l.DroppedEntries(); n != 2 {
+		t.Errorf("DroppedEntries = %d, want 2", n)
+	}
+	if text := metricsText(t, l); !strings.Contains(text, "logger_dropped_entries_total 2") {
+		t.Errorf("metrics missing dropped count:\n%s", text)
+	}
+
+	close(core.release)
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := core.written(); got != "1,2" {
+		t.Errorf("written = %s, want 1,2", got)
+	}
+}
+
+func TestAsyncSyncAndCloseDrain(t *testing.T) {
+	obs, logs := observer.New(zapcore.DebugLevel)
+	l, err := New(
+		WithFile(false, "", ""),
+		WithConsole(false, false),
+		WithCore(obs),
+		WithAsync(true, 16, OverflowBlock, ""),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	const workers, perWorker = 8, 500
+	logAll := func(phase string) {
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < perWorker; i++ {
+					l.Info(phase, zap.String("id", fmt.Sprintf("%d-%d", w, i)))
+				}
+			}(w)
+		}
+		wg.Wait()
+	}
+
+	// Sync 返回时已入队的日志全部写出
+	logAll("before sync")
+	if err := l.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if n := logs.FilterMessage("before sync").Len(); n != workers*perWorker {
+		t.Fatalf("after Sync got %d entries, want %d", n, workers*perWorker)
+	}
+
+	// Close 写完队列中剩余的日志
+	logAll("before close")
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, e := range logs.All() {
+		key := e.Message + "/" + e.ContextMap()["id"].(string)
+		if seen[key] {
+			t.Fatalf("duplicate entry %s", key)
+		}
+		seen[key] = true
+	}
+	if len(seen) != 2*workers*perWorker {
+		t.Errorf("got %d entries, want %d", len(seen), 2*workers*perWorker)
+	}
+	if n := l.DroppedEntries(); n != 0 {
+		t.Errorf("DroppedEntries = %d, want 0", n)
+	}
+}
@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// 调用位置格式
+const (
+	CallerShort  = "short"  // 所在目录与文件名，如 db/conn.go:42
+	CallerFull   = "full"   // 完整文件路径
+	CallerModule = "module" // 相对主模块根目录的路径，如 internal/db/conn.go:42；主模块以外的包为包路径加文件名
+	CallerTrim   = "trim"   // 包路径加文件名，并去掉 CallerTrimPrefixes 中第一个匹配的前缀
+)
+
+// mainModule 返回主模块路径，取自 debug.ReadBuildInfo，无法获取时为空
+var mainModule = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Path == "command-line-arguments" {
+		return ""
+	}
+	return info.Main.Path
+})
+
+// mainPackage 返回 main 包的导入路径，如 example.com/app/cmd/demo，取自 debug.ReadBuildInfo。
+// 函数名中 main 包只记为 "main"，借此得到与构建参数（如 -trimpath）无关的路径；无法获取时为空
+var mainPackage = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Path == "command-line-arguments" {
+		return ""
+	}
+	return info.Path
+})
+
+// newCallerEncoder 按格式创建调用位置编码器，prefixes 仅用于 trim 格式
+func newCallerEncoder(format string, prefixes []string) (zapcore.CallerEncoder, error) {
+	switch strings.ToLower(format) {
+	case CallerShort:
+		return zapcore.ShortCallerEncoder, nil
+	case CallerFull:
+		return zapcore.FullCallerEncoder, nil
+	case CallerModule:
+		if mod := mainModule(); mod != "" {
+			prefixes = []string{mod + "/"}
+		} else {
+			prefixes = nil
+		}
+		return (&callerTrimmer{prefixes: prefixes}).encode, nil
+	case CallerTrim:
+		return (&callerTrimmer{prefixes: prefixes}).encode, nil
+	default:
+		return nil, fmt.Errorf("unknown caller format %q, expected short, full, module or trim", format)
+	}
+}
+
+// callerTrimmer 以包路径定位文件并去掉前缀，结果按 PC 缓存
+type callerTrimmer struct {
+	prefixes []string
+	cache    sync.Map // uintptr -> string
+}
+
+func (t *callerTrimmer) encode(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
+	if !caller.Defined {
+		enc.AppendString("undefined")
+		return
+	}
+
+	var path string
+	if v, ok := t.cache.Load(caller.PC); ok && caller.PC != 0 {
+		path = v.(string)
+	} else {
+		path = t.path(caller)
+		if caller.PC != 0 {
+			t.cache.Store(caller.PC, path)
+		}
+	}
+	enc.AppendString(path + ":" + strconv.Itoa(caller.Line))
+}
+
+// path 返回去掉前缀后的文件路径，不含行号
+func (t *callerTrimmer) path(caller zapcore.EntryCaller) string {
+	file := caller.File
+	if i := strings.LastIndexByte(file, '/'); i >= 0 {
+		file = file[i+1:]
+	}
+
+	pkg := funcPackage(caller.Function)
+	if pkg == "main" {
+		pkg = mainPackage()
+	}
+	if pkg == "" {
+		return shortPath(caller.File)
+	}
+	path := pkg + "/" + file
+
+	for _, prefix := range t.prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return path[len(prefix):]
+		}
+	}
+	return path
+}
+
+// funcPackage 从完整函数名中取出包路径，如 "github.com/a/b/pkg.(*T).M" 返回 "github.com/a/b/pkg"
+func funcPackage(function string) string {
+	slash := strings.LastIndexByte(function, '/')
+	dot := strings.IndexByte(function[slash+1:], '.')
+	if dot < 0 {
+		return ""
+	}
+	return function[:slash+1+dot]
+}
+
+// shortPath 返回文件所在目录与文件名，与 ShortCallerEncoder 一致
+func shortPath(file string) string {
+	i := strings.LastIndexByte(file, '/')
+	if i < 0 {
+		return file
+	}
+	if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+		return file[j+1:]
+	}
+	return file
+}
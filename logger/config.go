@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadConfig 从 YAML 或 JSON 文件读取配置，按扩展名(.yaml/.yml/.json)识别格式。
+// 文件中未出现的字段使用默认值，未知字段视为错误
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return parseConfig(path, data)
+}
+
+// CheckConfig 读取并校验配置文件，不创建任何输出，供部署前检查使用
+func CheckConfig(path string) error {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	applyEnvironment(cfg)
+	return cfg.Validate()
+}
+
+// parseConfig 按文件扩展名解析配置内容
+func parseConfig(path string, data []byte) (*Config, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, fmt.Errorf("config file %s is empty", path)
+	}
+
+	cfg := defaultConfig()
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config file format: %q", ext)
+	}
+
+	recordFileSources(cfg, path, data)
+	return cfg, nil
+}
+
+// recordFileSources 记录文件中出现的字段来源
+func recordFileSources(cfg *Config, path string, data []byte) {
+	// JSON 是 YAML 的子集，统一按 YAML 解析出字段名
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return
+	}
+	for _, f := range configFields() {
+		if hasPath(raw, strings.Split(f.path, ".")) {
+			cfg.recordSource(f, "file:"+path)
+		}
+	}
+}
+
+// hasPath 判断嵌套 map 中是否存在指定路径
+func hasPath(m map[string]interface{}, keys []string) bool {
+	v, ok := m[keys[0]]
+	if !ok {
+		return false
+	}
+	if len(keys) == 1 {
+		return true
+	}
+	sub, ok := v.(map[string]interface{})
+	return ok && hasPath(sub, keys[1:])
+}
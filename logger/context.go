@@ -0,0 +1,168 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ctxKey context 中存放日志信息的 key
+type ctxKey struct{}
+
+// ctxValue context 中存放的 logger 与附加字段
+type ctxValue struct {
+	logger *zap.Logger // 为 nil 时使用调用时的全局 logger
+	helper *zap.Logger // logger 跳过一层调用位置，供 InfoCtx 等便捷方法使用
+	fields []zap.Field // 附加在 logger 之上的字段
+}
+
+// WithContext 将 logger 存入 ctx，之前通过 WithFields 附加的字段会被丢弃
+func WithContext(ctx context.Context, logger *zap.Logger) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	v := &ctxValue{logger: logger}
+	if logger != nil {
+		v.helper = logger.WithOptions(zap.AddCallerSkip(1))
+	}
+	return context.WithValue(ctx, ctxKey{}, v)
+}
+
+// WithFields 在 ctx 中追加字段，通过 Ctx 取出的 logger 会自动带上这些字段
+func WithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(fields) == 0 {
+		return ctx
+	}
+
+	v := &ctxValue{}
+	if parent, ok := ctx.Value(ctxKey{}).(*ctxValue); ok {
+		v.logger = parent.logger
+		v.helper = parent.helper
+		v.fields = make([]zap.Field, 0, len(parent.fields)+len(fields))
+		v.fields = append(v.fields, parent.fields...)
+	}
+	v.fields = append(v.fields, fields...)
+
+	return context.WithValue(ctx, ctxKey{}, v)
+}
+
+// FromContext 返回 ctx 中的 logger 并带上附加字段与 trace 字段；ctx 中没有时返回 L()
+func FromContext(ctx context.Context) *zap.Logger {
+	return fromContext(ctx, false)
+}
+
+// fromContext 实现 FromContext，helper 为 true 时返回的 logger 跳过便捷方法本身这一层调用位置
+func fromContext(ctx context.Context, helper bool) *zap.Logger {
+	logger := contextLogger(ctx, helper)
+	if fields := contextFields(ctx); len(fields) > 0 {
+		logger = logger.With(fields...)
+	}
+	return logger
+}
+
+// enabledContext 供便捷方法使用，级别未启用时返回 nil，不为被丢弃的日志创建带字段的 logger
+func enabledContext(ctx context.Context, lvl zapcore.Level) *zap.Logger {
+	logger := contextLogger(ctx, true)
+	if !logger.Core().Enabled(lvl) {
+		return nil
+	}
+	if fields := contextFields(ctx); len(fields) > 0 {
+		logger = logger.With(fields...)
+	}
+	return logger
+}
+
+// contextLogger 返回 ctx 中的 logger，不带附加字段；ctx 中没有时返回全局 logger
+func contextLogger(ctx context.Context, helper bool) *zap.Logger {
+	state := global.Load()
+	logger := state.base
+	if helper {
+		logger = state.helper
+	}
+	if ctx == nil {
+		return logger
+	}
+
+	if v, ok := ctx.Value(ctxKey{}).(*ctxValue); ok && v.logger != nil {
+		logger = v.logger
+		if helper {
+			logger = v.helper
+		}
+	}
+	return logger
+}
+
+// contextFields 返回 ctx 中的附加字段与 trace 字段，返回的切片不可修改
+func contextFields(ctx context.Context) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+
+	var fields []zap.Field
+	if v, ok := ctx.Value(ctxKey{}).(*ctxValue); ok {
+		fields = v.fields
+	}
+
+	// 附加 trace 字段，不修改 ctx 中保存的切片
+	if traceID, spanID, ok := traceFields(ctx); ok {
+		merged := make([]zap.Field, 0, len(fields)+2)
+		merged = append(merged, fields...)
+		fields = append(merged, traceID, spanID)
+	}
+	return fields
+}
+
+// Ctx 是 FromContext 的简写
+func Ctx(ctx context.Context) *zap.Logger {
+	return FromContext(ctx)
+}
+
+// 便捷方法 - 使用 ctx 中的 logger，调用位置为调用便捷方法之处。
+// DPanic 及以上级别即使未启用也需 panic 或退出，始终交给 zap 处理
+
+// DebugCtx 使用 ctx 中的 logger 输出 Debug 级别日志
+func DebugCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	if logger := enabledContext(ctx, zapcore.DebugLevel); logger != nil {
+		logger.Debug(msg, fields...)
+	}
+}
+
+// InfoCtx 使用 ctx 中的 logger 输出 Info 级别日志
+func InfoCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	if logger := enabledContext(ctx, zapcore.InfoLevel); logger != nil {
+		logger.Info(msg, fields...)
+	}
+}
+
+// WarnCtx 使用 ctx 中的 logger 输出 Warn 级别日志
+func WarnCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	if logger := enabledContext(ctx, zapcore.WarnLevel); logger != nil {
+		logger.Warn(msg, fields...)
+	}
+}
+
+// ErrorCtx 使用 ctx 中的 logger 输出 Error 级别日志
+func ErrorCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	if logger := enabledContext(ctx, zapcore.ErrorLevel); logger != nil {
+		logger.Error(msg, fields...)
+	}
+}
+
+// DPanicCtx 使用 ctx 中的 logger 输出 DPanic 级别日志
+func DPanicCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	fromContext(ctx, true).DPanic(msg, fields...)
+}
+
+// PanicCtx 使用 ctx 中的 logger 输出 Panic 级别日志
+func PanicCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	fromContext(ctx, true).Panic(msg, fields...)
+}
+
+// FatalCtx 使用 ctx 中的 logger 输出 Fatal 级别日志
+func FatalCtx(ctx context.Context, msg string, fields ...zap.Field) {
+	fromContext(ctx, true).Fatal(msg, fields...)
+}
@@ -0,0 +1,142 @@
+package logger_test
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/constellation39/framework/logger"
+	"github.com/constellation39/framework/logger/loggertest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithFields(t *testing.T) {
+	r := loggertest.New(t)
+	ctx := logger.WithContext(context.Background(), r.Logger.Logger)
+	parent := logger.WithFields(ctx, zap.String("request_id", "r1"))
+	child := logger.WithFields(parent, zap.String("user", "alice"))
+
+	logger.FromContext(child).Info("child", zap.Int("n", 1))
+	logger.FromContext(parent).Info("parent")
+	logger.FromContext(logger.WithFields(parent)).Info("no fields") // 不追加字段时原样返回
+
+	want := map[string]map[string]interface{}{
+		"child":     {"request_id": "r1", "user": "alice", "n": int64(1)},
+		"parent":    {"request_id": "r1"},
+		"no fields": {"request_id": "r1"},
+	}
+	entries := r.Entries()
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(want))
+	}
+	for _, e := range entries {
+		if got := e.ContextMap(); !reflect.DeepEqual(got, want[e.Message]) {
+			t.Errorf("%s: fields = %v, want %v", e.Message, got, want[e.Message])
+		}
+	}
+}
+
+func TestWithContextDropsFields(t *testing.T) {
+	first := loggertest.New(t)
+	second := loggertest.New(t)
+
+	ctx := logger.WithFields(logger.WithContext(context.Background(), first.Logger.Logger), zap.String("request_id", "r1"))
+	ctx = logger.WithContext(ctx, second.Named("db"))
+	logger.InfoCtx(ctx, "query")
+
+	if n := len(first.Entries()); n != 0 {
+		t.Errorf("replaced logger got %d entries", n)
+	}
+	entries := second.Entries()
+	if len(entries) != 1 || entries[0].LoggerName != "db" || len(entries[0].Context) != 0 {
+		t.Errorf("entries = %+v, want one db entry without fields", entries)
+	}
+}
+
+func TestFromContextUsesGlobal(t *testing.T) {
+	r := loggertest.Install(t)
+
+	var nilCtx context.Context
+	logger.FromContext(nilCtx).Info("nil")
+	logger.Ctx(context.Background()).Info("background")
+	// 只有字段时使用调用时的全局 logger
+	ctx := logger.WithFields(context.Background(), zap.String("request_id", "r1"))
+	logger.WarnCtx(ctx, "fields")
+
+	if got := r.Find(zapcore.InfoLevel, "nil"); len(got) != 1 {
+		t.Errorf("nil ctx: got %d entries", len(got))
+	}
+	if got := r.Find(zapcore.InfoLevel, "background"); len(got) != 1 {
+		t.Errorf("background ctx: got %d entries", len(got))
+	}
+	if got := r.Find(zapcore.WarnLevel, "fields", zap.String("request_id", "r1")); len(got) != 1 {
+		t.Errorf("fields ctx: got %d entries", len(got))
+	}
+}
+
+func TestContextTraceFields(t *testing.T) {
+	r := loggertest.New(t)
+	ctx := logger.WithContext(context.Background(), r.Logger.Logger)
+	ctx = logger.WithFields(ctx, zap.String("request_id", "r1"))
+	ctx = logger.WithTraceparent(ctx, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	logger.InfoCtx(ctx, "traced")
+
+	want := []zap.Field{
+		zap.String("request_id", "r1"),
+		zap.String(logger.TraceIDKey, "4bf92f3577b34da6a3ce929d0e0e4736"),
+		zap.String(logger.SpanIDKey, "00f067aa0ba902b7"),
+	}
+	if got := r.Find(zapcore.InfoLevel, "traced", want...); len(got) != 1 {
+		t.Errorf("entries = %+v", r.Entries())
+	}
+}
+
+// withCountingCore 统计 With 调用次数
+type withCountingCore struct {
+	zapcore.Core
+	withs *atomic.Int32
+}
+
+func (c withCountingCore) With(fields []zapcore.Field) zapcore.Core {
+	c.withs.Add(1)
+	return withCountingCore{Core: c.Core.With(fields), withs: c.withs}
+}
+
+func (c withCountingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func TestCtxHelpersSkipDisabledLevels(t *testing.T) {
+	var withs atomic.Int32
+	enc := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	core := withCountingCore{Core: zapcore.NewCore(enc, zapcore.AddSync(io.Discard), zapcore.InfoLevel), withs: &withs}
+	ctx := logger.WithFields(logger.WithContext(context.Background(), zap.New(core)), zap.String("request_id", "r1"))
+
+	logger.DebugCtx(ctx, "hidden")
+	if n := withs.Load(); n != 0 {
+		t.Errorf("DebugCtx on a disabled level called With %d times", n)
+	}
+
+	logger.InfoCtx(ctx, "shown")
+	logger.ErrorCtx(ctx, "shown")
+	if n := withs.Load(); n != 2 {
+		t.Errorf("With called %d times for enabled levels, want 2", n)
+	}
+}
+
+func BenchmarkDebugCtxDisabled(b *testing.B) {
+	installDiscard(b)
+	logger.GetGlobal().SetLevel(zapcore.InfoLevel)
+	ctx := logger.WithFields(context.Background(), zap.String("request_id", "r1"))
+	b.ReportAllocs()
+	for b.Loop() {
+		logger.DebugCtx(ctx, "msg", zap.Int("n", 1))
+	}
+}
@@ -0,0 +1,169 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"time"
+
+	"github.com/constellation39/framework/buildinfo"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxGoroutineDump 崩溃报告中所有协程堆栈的最大字节数
+const maxGoroutineDump = 64 << 20
+
+// Go 启动协程执行 fn，fn 中的 panic 由全局 logger 记录（未初始化时重新 panic），见 Logger.Go
+func Go(name string, fn func()) {
+	go func() {
+		defer handleRecovered(GetGlobal(), name)
+		fn()
+	}()
+}
+
+// Recover 需以 defer logger.Recover() 的形式调用，使用全局 logger 记录 panic，见 Logger.Recover
+func Recover() {
+	if p := recover(); p != nil {
+		handlePanic(GetGlobal(), "", p)
+	}
+}
+
+// Go 启动名为 name 的协程执行 fn。fn 中的 panic 会连同完整堆栈记录为 Error 日志，
+// 并按配置写入崩溃报告；CrashRepanic 为 false 时该协程结束而进程继续运行
+func (l *Logger) Go(name string, fn func()) {
+	go func() {
+		defer handleRecovered(l, name)
+		fn()
+	}()
+}
+
+// Recover 需以 defer l.Recover() 的形式调用，处理方式同 Logger.Go
+func (l *Logger) Recover() {
+	if p := recover(); p != nil {
+		handlePanic(l, "", p)
+	}
+}
+
+// handleRecovered 供 Go 启动的协程 defer 调用，recover 须在被 defer 的函数中直接调用
+func handleRecovered(l *Logger, name string) {
+	if p := recover(); p != nil {
+		handlePanic(l, name, p)
+	}
+}
+
+// handlePanic 记录 panic、写入崩溃报告，并按配置重新 panic。
+// logger 未初始化时将堆栈写到标准错误后重新 panic，与未捕获时的行为一致
+func handlePanic(l *Logger, name string, p any) {
+	stack := debug.Stack()
+
+	if l == nil {
+		fmt.Fprintf(os.Stderr, "panic: %v [recovered]\n\n%s", p, stack)
+		panic(p)
+	}
+	cfg := l.sinks.current().config
+
+	fields := []zap.Field{
+		zap.String("panic", fmt.Sprint(p)),
+		zap.String("stack", string(stack)),
+	}
+	if name != "" {
+		fields = append(fields, zap.String("goroutine", name))
+	}
+	if cfg.CrashReport {
+		path, err := writeCrashReport(cfg, name, p, stack)
+		if path != "" {
+			fields = append(fields, zap.String("crash_report", path))
+		}
+		if err != nil {
+			fields = append(fields, zap.String("crash_report_error", err.Error()))
+		}
+	}
+
+	// 堆栈已单独记录，调用位置为本文件，均不输出
+	l.Logger.WithOptions(zap.WithCaller(false), zap.AddStacktrace(zapcore.InvalidLevel)).Error("panic recovered", fields...)
+	_ = l.Sync()
+
+	if cfg.CrashRepanic {
+		panic(p)
+	}
+}
+
+// writeCrashReport 写入崩溃报告：panic 值、构建信息、panic 协程堆栈与所有协程堆栈。
+// 写入成功后按 CrashMaxReports 清理旧报告，清理失败时仍返回报告路径
+func writeCrashReport(cfg *Config, name string, p any, stack []byte) (string, error) {
+	dir := firstNonEmpty(cfg.CrashDir, cfg.LogDir, ".")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create crash report dir: %w", err)
+	}
+
+	now := time.Now()
+	path := filepath.Join(dir, fmt.Sprintf("crash-%s-%d.txt", now.Format("20060102-150405.000000"), os.Getpid()))
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "panic: %v\n", p)
+	if name != "" {
+		fmt.Fprintf(&b, "goroutine: %s\n", name)
+	}
+	fmt.Fprintf(&b, "time: %s\n", now.Format(time.RFC3339Nano))
+	fmt.Fprintf(&b, "pid: %d\n", os.Getpid())
+	if len(os.Args) > 0 {
+		// 参数中可能含有敏感信息，只记录程序名
+		fmt.Fprintf(&b, "command: %s\n", os.Args[0])
+	}
+	fmt.Fprintf(&b, "go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if info, err := buildinfo.Get().JSON(); err == nil {
+		fmt.Fprintf(&b, "build: %s\n", info)
+	}
+
+	b.WriteString("\npanic stack:\n")
+	b.Write(stack)
+	b.WriteString("\nall goroutines:\n")
+	b.Write(goroutineDump())
+
+	if err := os.WriteFile(path, b.Bytes(), 0644); err != nil {
+		return "", fmt.Errorf("failed to write crash report: %w", err)
+	}
+	return path, pruneCrashReports(dir, cfg.CrashMaxReports)
+}
+
+// pruneCrashReports 删除 dir 中最旧的崩溃报告，只保留 keep 个，keep 为 0 时不清理。
+// 报告文件名以时间开头，按文件名排序即按时间排序
+func pruneCrashReports(dir string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "crash-*.txt"))
+	if err != nil || len(paths) <= keep {
+		return err
+	}
+	sort.Strings(paths)
+
+	var errs []error
+	for _, path := range paths[:len(paths)-keep] {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to remove old crash reports: %w", err)
+	}
+	return nil
+}
+
+// goroutineDump 返回所有协程的堆栈，超出 maxGoroutineDump 时截断
+func goroutineDump() []byte {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxGoroutineDump {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
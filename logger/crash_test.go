@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// crashingHandler 在 Recover 保护下 panic
+func crashingHandler(l *Logger) {
+	defer l.Recover()
+	panic("boom")
+}
+
+// panicEntry 返回唯一一条 panic 日志的字段
+func panicEntry(t *testing.T, logs *observer.ObservedLogs) map[string]interface{} {
+	t.Helper()
+	entries := logs.FilterMessage("panic recovered").All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d panic entries, want 1", len(entries))
+	}
+	return entries[0].ContextMap()
+}
+
+func crashReports(t *testing.T, dir string) []string {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, "crash-*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return paths
+}
+
+func TestCrashReportDisabledByDefault(t *testing.T) {
+	dir := t.TempDir()
+	l, logs := newObservedLogger(t, WithFile(true, dir, "app"))
+
+	crashingHandler(l)
+
+	fields := panicEntry(t, logs)
+	if fields["panic"] != "boom" || !strings.Contains(fields["stack"].(string), "logger.crashingHandler") {
+		t.Errorf("fields = %v", fields)
+	}
+	if _, ok := fields["crash_report"]; ok {
+		t.Errorf("crash report written by default: %v", fields["crash_report"])
+	}
+	if paths := crashReports(t, dir); len(paths) != 0 {
+		t.Errorf("crash reports = %q, want none", paths)
+	}
+}
+
+func TestCrashReportContents(t *testing.T) {
+	dir := t.TempDir()
+	l, logs := newObservedLogger(t, WithCrashReport(true, dir, false))
+
+	crashingHandler(l)
+
+	path, _ := panicEntry(t, logs)["crash_report"].(string)
+	if filepath.Dir(path) != dir {
+		t.Fatalf("crash_report = %q, want a file in %s", path, dir)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := string(data)
+	for _, want := range []string{
+		"panic: boom\n",
+		"pid: " + strconv.Itoa(os.Getpid()) + "\n",
+		"go: " + runtime.Version() + " " + runtime.GOOS + "/" + runtime.GOARCH + "\n",
+		"\npanic stack:\n",
+		"logger.crashingHandler",
+		"\nall goroutines:\n",
+		"testing.tRunner",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "goroutine: ") {
+		t.Error("Recover report names a goroutine")
+	}
+}
+
+func TestCrashReportRetention(t *testing.T) {
+	dir := t.TempDir()
+	old := []string{
+		"crash-20200101-000000.000001-1.txt",
+		"crash-20200101-000000.000002-1.txt",
+		"crash-20200101-000000.000003-1.txt",
+	}
+	for _, name := range append(old, "notes.txt") {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l, logs := newObservedLogger(t, WithCrashReport(true, dir, false), WithCrashMaxReports(2))
+
+	crashingHandler(l)
+
+	fields := panicEntry(t, logs)
+	if _, ok := fields["crash_report_error"]; ok {
+		t.Fatalf("crash_report_error = %v", fields["crash_report_error"])
+	}
+	want := []string{filepath.Join(dir, old[2]), fields["crash_report"].(string)}
+	got := crashReports(t, dir)
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("crash reports = %q, want %q", got, want)
+	}
+	// 其他文件不受影响
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCrashRepanic(t *testing.T) {
+	l, logs := newObservedLogger(t, WithCrashReport(false, "", true))
+
+	var recovered interface{}
+	func() {
+		defer func() { recovered = recover() }()
+		crashingHandler(l)
+	}()
+
+	if recovered != "boom" {
+		t.Errorf("recovered = %v, want boom", recovered)
+	}
+	// 重新 panic 前已记录
+	if fields := panicEntry(t, logs); fields["panic"] != "boom" {
+		t.Errorf("fields = %v", fields)
+	}
+}
+
+func TestGoRecoversInGoroutine(t *testing.T) {
+	dir := t.TempDir()
+	l, logs := newObservedLogger(t, WithCrashReport(true, dir, false))
+
+	l.Go("worker", func() {
+		var m map[string]int
+		m["x"] = 1
+	})
+	waitFor(t, "panic entry", func() bool { return logs.FilterMessage("panic recovered").Len() == 1 })
+
+	fields := panicEntry(t, logs)
+	if fields["goroutine"] != "worker" || !strings.Contains(fields["panic"].(string), "nil map") {
+		t.Errorf("fields = %v", fields)
+	}
+	if !strings.Contains(fields["stack"].(string), "TestGoRecoversInGoroutine.func1") {
+		t.Errorf("stack does not include the goroutine function:\n%s", fields["stack"])
+	}
+	data, err := os.ReadFile(fields["crash_report"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "goroutine: worker\n") {
+		t.Errorf("report does not name the goroutine:\n%s", data)
+	}
+}
@@ -0,0 +1,267 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultEnvPrefix 环境变量默认前缀
+const DefaultEnvPrefix = "LOG"
+
+// 字段来源
+const (
+	SourceDefault     = "default"     // 默认值
+	SourceOption      = "option"      // 代码中的选项或运行期修改
+	SourceEnvironment = "environment" // 由 Environment=production 自动调整
+)
+
+// valueSource 字段来源及记录时的取值，取值变化后视为被后续选项覆盖
+type valueSource struct {
+	from  string
+	value string
+}
+
+// configField Config 中的一个可配置字段，嵌套结构体展开为子字段
+type configField struct {
+	path  string // json 标签路径，如 "redaction.keys"
+	index []int
+}
+
+// configFields 返回 Config 的全部可配置字段
+var configFields = sync.OnceValue(func() []configField {
+	var fields []configField
+	var walk func(t reflect.Type, prefix string, index []int)
+	walk = func(t reflect.Type, prefix string, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := strings.Split(f.Tag.Get("json"), ",")[0]
+			if !f.IsExported() || tag == "" || tag == "-" {
+				continue
+			}
+			path := tag
+			if prefix != "" {
+				path = prefix + "." + tag
+			}
+			idx := append(index[:len(index):len(index)], i)
+			if f.Type.Kind() == reflect.Struct {
+				walk(f.Type, path, idx)
+				continue
+			}
+			fields = append(fields, configField{path: path, index: idx})
+		}
+	}
+	walk(reflect.TypeOf(Config{}), "", nil)
+	return fields
+})
+
+// lookupConfigField 按路径查找字段
+func lookupConfigField(path string) (configField, bool) {
+	for _, f := range configFields() {
+		if f.path == path {
+			return f, true
+		}
+	}
+	return configField{}, false
+}
+
+// envName 字段对应的环境变量名，如 LOG_ROTATION_SIZE、LOG_REDACTION_KEYS
+func envName(prefix, path string) string {
+	return prefix + "_" + strings.ToUpper(strings.ReplaceAll(path, ".", "_"))
+}
+
+// WithEnv 从环境变量读取全部配置，变量名为 <prefix>_<字段 json 标签大写>，
+// 嵌套字段以下划线连接，如 LOG_ROTATION_SIZE、LOG_REDACTION_KEYS。
+// 字符串切片用逗号分隔，map 写作 "k1=v1,k2=v2"，Outputs 使用 JSON。
+// 取值按字段类型严格解析，所有错误由 New 一并返回；空值视为未设置。prefix 为空时使用 LOG
+func WithEnv(prefix string) Option {
+	return func(c *Config) {
+		if prefix == "" {
+			prefix = DefaultEnvPrefix
+		}
+		c.addErr(bindEnv(c, prefix, os.LookupEnv))
+	}
+}
+
+// bindEnv 按字段绑定环境变量
+func bindEnv(c *Config, prefix string, lookup func(string) (string, bool)) error {
+	var errs []error
+	for _, f := range configFields() {
+		name := envName(prefix, f.path)
+		raw, ok := lookup(name)
+		if !ok || strings.TrimSpace(raw) == "" {
+			continue
+		}
+		if err := c.setFromEnv(f, name, raw); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// setFromEnv 解析环境变量并写入字段，同时记录来源
+func (c *Config) setFromEnv(f configField, name, raw string) error {
+	v := reflect.ValueOf(c).Elem().FieldByIndex(f.index)
+	if err := setFromString(v, raw); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	c.recordSource(f, "env:"+name)
+	return nil
+}
+
+// setFromString 按字段类型严格解析字符串
+func setFromString(v reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid bool value %q", raw)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer value %q", raw)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer value %q", raw)
+		}
+		v.SetUint(n)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return setFromJSON(v, raw)
+		}
+		items := splitList(raw)
+		s := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			s.Index(i).SetString(item)
+		}
+		v.Set(s)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String || v.Type().Elem().Kind() != reflect.String {
+			return setFromJSON(v, raw)
+		}
+		m := reflect.MakeMap(v.Type())
+		for _, item := range splitList(raw) {
+			key, value, ok := strings.Cut(item, "=")
+			if !ok || strings.TrimSpace(key) == "" {
+				return fmt.Errorf("invalid map entry %q, expected key=value", item)
+			}
+			m.SetMapIndex(reflect.ValueOf(strings.TrimSpace(key)), reflect.ValueOf(strings.TrimSpace(value)))
+		}
+		v.Set(m)
+	default:
+		return setFromJSON(v, raw)
+	}
+	return nil
+}
+
+// setFromJSON 以 JSON 解析复杂类型
+func setFromJSON(v reflect.Value, raw string) error {
+	ptr := reflect.New(v.Type())
+	dec := json.NewDecoder(strings.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(ptr.Interface()); err != nil {
+		return fmt.Errorf("invalid JSON value: %w", err)
+	}
+	v.Set(ptr.Elem())
+	return nil
+}
+
+// splitList 按逗号拆分并去除空白项
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// addErr 记录选项执行中的错误
+func (c *Config) addErr(err error) {
+	if err != nil {
+		c.optErr = errors.Join(c.optErr, err)
+	}
+}
+
+// recordSource 记录字段来源
+func (c *Config) recordSource(f configField, from string) {
+	sources := make(map[string]valueSource, len(c.sources)+1)
+	for k, v := range c.sources {
+		sources[k] = v
+	}
+	sources[f.path] = valueSource{from: from, value: c.fieldString(f)}
+	c.sources = sources
+}
+
+// recordSourceByPath 按字段路径记录来源
+func (c *Config) recordSourceByPath(path, from string) {
+	if f, ok := lookupConfigField(path); ok {
+		c.recordSource(f, from)
+	}
+}
+
+func (c *Config) fieldString(f configField) string {
+	return fmt.Sprintf("%v", reflect.ValueOf(c).Elem().FieldByIndex(f.index).Interface())
+}
+
+// Source 返回字段的来源，path 为 json 标签路径，如 "rotation_size"、"redaction.keys"。
+// 取值为 default、option、environment、env:<变量名> 或 file:<文件路径>
+func (c *Config) Source(path string) string {
+	f, ok := lookupConfigField(path)
+	if !ok {
+		return ""
+	}
+	return c.source(f, reflect.ValueOf(defaultConfig()).Elem())
+}
+
+// Sources 返回所有字段的来源
+func (c *Config) Sources() map[string]string {
+	defaults := reflect.ValueOf(defaultConfig()).Elem()
+	fields := configFields()
+	sources := make(map[string]string, len(fields))
+	for _, f := range fields {
+		sources[f.path] = c.source(f, defaults)
+	}
+	return sources
+}
+
+func (c *Config) source(f configField, defaults reflect.Value) string {
+	if s, ok := c.sources[f.path]; ok && s.value == c.fieldString(f) {
+		return s.from
+	}
+	current := reflect.ValueOf(c).Elem().FieldByIndex(f.index).Interface()
+	if reflect.DeepEqual(current, defaults.FieldByIndex(f.index).Interface()) {
+		return SourceDefault
+	}
+	return SourceOption
+}
+
+// EnvNames 返回全部字段对应的环境变量名，prefix 为空时使用 LOG
+func EnvNames(prefix string) []string {
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+	fields := configFields()
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		names = append(names, envName(prefix, f.path))
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,194 @@
+package logger_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/constellation39/framework/logger"
+	"go.uber.org/zap/zapcore"
+)
+
+// newEnvLogger 创建不写文件与控制台的 logger，opts 在默认选项之后应用
+func newEnvLogger(t *testing.T, opts ...logger.Option) *logger.Logger {
+	t.Helper()
+	base := []logger.Option{
+		logger.WithFile(false, "", ""),
+		logger.WithConsole(false, false),
+		logger.WithCore(zapcore.NewNopCore()),
+	}
+	l, err := logger.New(append(base, opts...)...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	return l
+}
+
+func TestWithEnvBindsFields(t *testing.T) {
+	t.Setenv("APP_LEVEL", "warn")
+	t.Setenv("APP_ROTATION_SIZE", "64")
+	t.Setenv("APP_ENABLE_SAMPLING", "true")
+	t.Setenv("APP_ERROR_FILE_LEVELS", "error, fatal,")
+	t.Setenv("APP_MODULE_LEVELS", "db.*=debug, http=error")
+	t.Setenv("APP_REDACTION_KEYS", "password,*token*")
+	t.Setenv("APP_REDACTION_MODE", "hash")
+	t.Setenv("APP_RATE_LIMIT_WINDOW", "30")
+	t.Setenv("APP_RATE_LIMIT_BUDGETS", `{"info": 10}`)
+	t.Setenv("APP_OUTPUTS", `[{"type": "stderr", "min_level": "error"}]`)
+	t.Setenv("APP_CALLER_FORMAT", " ") // 空值视为未设置
+
+	cfg := newEnvLogger(t, logger.WithEnv("APP")).GetConfig()
+
+	if cfg.Level != "warn" || cfg.RotationSize != 64 || !cfg.EnableSampling {
+		t.Errorf("level = %s, rotation_size = %d, enable_sampling = %v", cfg.Level, cfg.RotationSize, cfg.EnableSampling)
+	}
+	if want := []string{"error", "fatal"}; !reflect.DeepEqual(cfg.ErrorFileLevels, want) {
+		t.Errorf("error_file_levels = %q, want %q", cfg.ErrorFileLevels, want)
+	}
+	if want := map[string]string{"db.*": "debug", "http": "error"}; !reflect.DeepEqual(cfg.ModuleLevels, want) {
+		t.Errorf("module_levels = %v, want %v", cfg.ModuleLevels, want)
+	}
+
+	// 嵌套结构体的字段以下划线连接
+	if want := []string{"password", "*token*"}; !reflect.DeepEqual(cfg.Redaction.Keys, want) || cfg.Redaction.Mode != "hash" {
+		t.Errorf("redaction = %+v", cfg.Redaction)
+	}
+	if cfg.RateLimit.Window != 30 || !reflect.DeepEqual(cfg.RateLimit.Budgets, map[string]int{"info": 10}) {
+		t.Errorf("rate_limit = %+v", cfg.RateLimit)
+	}
+
+	// 结构体切片使用 JSON
+	if len(cfg.Outputs) != 1 || cfg.Outputs[0].Type != "stderr" || cfg.Outputs[0].MinLevel != "error" {
+		t.Errorf("outputs = %+v", cfg.Outputs)
+	}
+	if cfg.CallerFormat != "" {
+		t.Errorf("caller_format = %q, want unset", cfg.CallerFormat)
+	}
+}
+
+func TestWithEnvStrictParseErrors(t *testing.T) {
+	t.Setenv("LOG_ENABLE_CONSOLE", "yes please")
+	t.Setenv("LOG_MAX_AGE", "7d")
+	t.Setenv("LOG_MODULE_LEVELS", "db")
+	t.Setenv("LOG_OUTPUTS", `[{"type": "stdout", "colour": true}]`)
+
+	l, err := logger.New(logger.WithEnv(""))
+	if err == nil {
+		_ = l.Close()
+		t.Fatal("expected error")
+	}
+
+	// 所有错误一并返回
+	for _, name := range []string{"LOG_ENABLE_CONSOLE", "LOG_MAX_AGE", "LOG_MODULE_LEVELS", "LOG_OUTPUTS"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error does not mention %s: %v", name, err)
+		}
+	}
+}
+
+func TestWithEnvAutoConfigLegacyPriority(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        map[string]string
+		wantEnv    string
+		wantSource string
+	}{
+		{
+			name:       "LOG_ENV before ENV",
+			env:        map[string]string{"LOG_ENV": "prod", "ENV": "development"},
+			wantEnv:    "production",
+			wantSource: "env:LOG_ENV",
+		},
+		{
+			name:       "ENV when LOG_ENV is empty",
+			env:        map[string]string{"LOG_ENV": "", "ENV": "PRODUCTION"},
+			wantEnv:    "production",
+			wantSource: "env:ENV",
+		},
+		{
+			name:       "full name before legacy names",
+			env:        map[string]string{"LOG_ENVIRONMENT": "development", "LOG_ENV": "production"},
+			wantEnv:    "development",
+			wantSource: "env:LOG_ENVIRONMENT",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg := newEnvLogger(t, logger.WithEnvAutoConfig()).GetConfig()
+			if cfg.Environment != tt.wantEnv {
+				t.Errorf("environment = %q, want %q", cfg.Environment, tt.wantEnv)
+			}
+			if got := cfg.Source("environment"); got != tt.wantSource {
+				t.Errorf("environment source = %q, want %q", got, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestWithEnvAutoConfigLegacyDir(t *testing.T) {
+	t.Setenv("LOG_DIR", "/tmp/legacy")
+	t.Setenv("LOG_LOG_DIR", "/tmp/full")
+	t.Setenv("LOG_FILE_ENABLED", "false")
+
+	cfg := newEnvLogger(t, logger.WithEnvAutoConfig()).GetConfig()
+	if cfg.LogDir != "/tmp/full" || cfg.Source("log_dir") != "env:LOG_LOG_DIR" {
+		t.Errorf("log_dir = %q from %s", cfg.LogDir, cfg.Source("log_dir"))
+	}
+	if cfg.EnableFile || cfg.Source("enable_file") != "env:LOG_FILE_ENABLED" {
+		t.Errorf("enable_file = %v from %s", cfg.EnableFile, cfg.Source("enable_file"))
+	}
+}
+
+func TestSources(t *testing.T) {
+	path := writeConfigFile(t, "log.yaml", "environment: production\nmax_age: 3\n")
+	t.Setenv("LOG_ROTATION_SIZE", "64")
+	t.Setenv("LOG_ROTATION_COUNT", "5")
+
+	l := newEnvLogger(t,
+		logger.WithConfigFile(path),
+		logger.WithFile(false, "", ""),
+		logger.WithConsole(false, false),
+		logger.WithEnv(""),
+		logger.WithLevel("warn"),
+		logger.WithRotationCount(7), // 覆盖环境变量
+	)
+	sources := l.GetConfig().Sources()
+
+	want := map[string]string{
+		"environment":     "file:" + path,
+		"max_age":         "file:" + path,
+		"rotation_size":   "env:LOG_ROTATION_SIZE",
+		"rotation_count":  logger.SourceOption,
+		"level":           logger.SourceOption,
+		"encoding":        logger.SourceEnvironment,
+		"enable_sampling": logger.SourceEnvironment,
+		"filename":        logger.SourceDefault,
+	}
+	for path, from := range want {
+		if got := sources[path]; got != from {
+			t.Errorf("source of %s = %q, want %q", path, got, from)
+		}
+	}
+	if _, ok := sources["redaction.keys"]; !ok {
+		t.Error("Sources missing nested field redaction.keys")
+	}
+}
+
+func TestEnvNames(t *testing.T) {
+	names := logger.EnvNames("APP")
+	for _, want := range []string{"APP_LEVEL", "APP_ROTATION_SIZE", "APP_REDACTION_KEYS", "APP_RATE_LIMIT_BUDGETS"} {
+		found := false
+		for _, name := range names {
+			if name == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("EnvNames missing %s", want)
+		}
+	}
+}
@@ -1,15 +1,43 @@
 package logger
 
 import (
+	"fmt"
+	"net/http"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
-var (
-	globalLogger atomic.Value
-	nopLogger    = zap.NewNop()
-)
+var nopLogger = zap.NewNop()
+
+// globalState 全局 logger 及预先构建的派生 logger，SetGlobal 时整体替换
+type globalState struct {
+	logger *Logger // 未初始化时为 nil
+	base   *zap.Logger
+	sugar  *zap.SugaredLogger
+	helper *zap.Logger // 供包级便捷方法使用，调用位置跳过便捷方法本身这一层
+	hsugar *zap.SugaredLogger
+}
+
+var global atomic.Pointer[globalState]
+
+func init() {
+	global.Store(newGlobalState(nil))
+}
+
+func newGlobalState(logger *Logger) *globalState {
+	s := &globalState{logger: logger, base: nopLogger, sugar: nopLogger.Sugar()}
+	if logger != nil {
+		s.base = logger.Logger
+		s.sugar = logger.sugar
+	}
+	s.helper = s.base.WithOptions(zap.AddCallerSkip(1))
+	s.hsugar = s.helper.Sugar()
+	return s
+}
 
 // Init 初始化全局日志
 func Init(opts ...Option) error {
@@ -19,6 +47,10 @@ func Init(opts ...Option) error {
 	}
 
 	SetGlobal(logger)
+
+	if logger.sinks.current().config.RedirectStdLog {
+		return logger.RedirectStdLog()
+	}
 	return nil
 }
 
@@ -29,35 +61,38 @@ func MustInit(opts ...Option) {
 	}
 }
 
-// SetGlobal 设置全局日志实例
+// SetGlobal 设置全局日志实例，传入 nil 时恢复为未初始化状态
 func SetGlobal(logger *Logger) {
-	globalLogger.Store(logger)
+	global.Store(newGlobalState(logger))
 	// 同时设置 zap 的全局 logger
+	if logger == nil {
+		zap.ReplaceGlobals(nopLogger)
+		setSlogDefault(nil)
+		return
+	}
 	zap.ReplaceGlobals(logger.Logger)
+
+	// 按配置同时设置 slog 的默认 logger
+	if logger.sinks.current().config.SlogDefault {
+		setSlogDefault(logger)
+	} else {
+		setSlogDefault(nil)
+	}
 }
 
 // GetGlobal 获取全局日志实例
 func GetGlobal() *Logger {
-	if v := globalLogger.Load(); v != nil {
-		return v.(*Logger)
-	}
-	return nil
+	return global.Load().logger
 }
 
 // L 返回全局 Logger（如果未初始化则返回 nop logger）
 func L() *zap.Logger {
-	if v := globalLogger.Load(); v != nil {
-		return v.(*Logger).Logger
-	}
-	return nopLogger
+	return global.Load().base
 }
 
 // S 返回全局 SugaredLogger（如果未初始化则返回 nop logger）
 func S() *zap.SugaredLogger {
-	if v := globalLogger.Load(); v != nil {
-		return v.(*Logger).Sugar()
-	}
-	return nopLogger.Sugar()
+	return global.Load().sugar
 }
 
 // Sync 同步全局日志
@@ -76,76 +111,90 @@ func Close() error {
 	return nil
 }
 
-// 便捷方法 - 直接使用全局 logger
+// Reload 使用新配置重新加载全局日志，配置无效时保留原配置
+func Reload(cfg *Config) error {
+	logger := GetGlobal()
+	if logger == nil {
+		return fmt.Errorf("logger not initialized")
+	}
+	return logger.Reload(cfg)
+}
+
+// WatchConfig 监听配置文件，内容变化时重新加载当前的全局日志
+func WatchConfig(path string, interval time.Duration) (stop func(), err error) {
+	return watchConfig(path, interval, GetGlobal)
+}
+
+// 便捷方法 - 直接使用全局 logger，调用位置为调用便捷方法之处
 
 // Debug 输出 Debug 级别日志
 func Debug(msg string, fields ...zap.Field) {
-	L().Debug(msg, fields...)
+	global.Load().helper.Debug(msg, fields...)
 }
 
 // Info 输出 Info 级别日志
 func Info(msg string, fields ...zap.Field) {
-	L().Info(msg, fields...)
+	global.Load().helper.Info(msg, fields...)
 }
 
 // Warn 输出 Warn 级别日志
 func Warn(msg string, fields ...zap.Field) {
-	L().Warn(msg, fields...)
+	global.Load().helper.Warn(msg, fields...)
 }
 
 // Error 输出 Error 级别日志
 func Error(msg string, fields ...zap.Field) {
-	L().Error(msg, fields...)
+	global.Load().helper.Error(msg, fields...)
 }
 
 // DPanic 输出 DPanic 级别日志
 func DPanic(msg string, fields ...zap.Field) {
-	L().DPanic(msg, fields...)
+	global.Load().helper.DPanic(msg, fields...)
 }
 
 // Panic 输出 Panic 级别日志
 func Panic(msg string, fields ...zap.Field) {
-	L().Panic(msg, fields...)
+	global.Load().helper.Panic(msg, fields...)
 }
 
 // Fatal 输出 Fatal 级别日志
 func Fatal(msg string, fields ...zap.Field) {
-	L().Fatal(msg, fields...)
+	global.Load().helper.Fatal(msg, fields...)
 }
 
 // Debugf 格式化输出 Debug 级别日志
 func Debugf(template string, args ...interface{}) {
-	S().Debugf(template, args...)
+	global.Load().hsugar.Debugf(template, args...)
 }
 
 // Infof 格式化输出 Info 级别日志
 func Infof(template string, args ...interface{}) {
-	S().Infof(template, args...)
+	global.Load().hsugar.Infof(template, args...)
 }
 
 // Warnf 格式化输出 Warn 级别日志
 func Warnf(template string, args ...interface{}) {
-	S().Warnf(template, args...)
+	global.Load().hsugar.Warnf(template, args...)
 }
 
 // Errorf 格式化输出 Error 级别日志
 func Errorf(template string, args ...interface{}) {
-	S().Errorf(template, args...)
+	global.Load().hsugar.Errorf(template, args...)
 }
 
 // DPanicf 格式化输出 DPanic 级别日志
 func DPanicf(template string, args ...interface{}) {
-	S().DPanicf(template, args...)
+	global.Load().hsugar.DPanicf(template, args...)
 }
 
 // Panicf 格式化输出 Panic 级别日志
 func Panicf(template string, args ...interface{}) {
-	S().Panicf(template, args...)
+	global.Load().hsugar.Panicf(template, args...)
 }
 
 // Fatalf 格式化输出 Fatal 级别日志
 func Fatalf(template string, args ...interface{}) {
-	S().Fatalf(template, args...)
+	global.Load().hsugar.Fatalf(template, args...)
 }
 
 // With 创建带有字段的 logger
@@ -157,3 +206,71 @@ func With(fields ...zap.Field) *zap.Logger {
 func WithOptions(opts ...zap.Option) *zap.Logger {
 	return L().WithOptions(opts...)
 }
+
+// GetLevel 返回全局日志当前级别（未初始化时返回 zapcore.InvalidLevel）
+func GetLevel() zapcore.Level {
+	if logger := GetGlobal(); logger != nil {
+		return logger.Level()
+	}
+	return zapcore.InvalidLevel
+}
+
+// SetLevel 在运行期调整全局日志级别
+func SetLevel(level string) error {
+	logger := GetGlobal()
+	if logger == nil {
+		return fmt.Errorf("logger not initialized")
+	}
+	return logger.SetLevelString(level)
+}
+
+// LevelHandler 返回调整全局日志级别的 http.Handler，始终作用于当前的全局 logger
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveLevel(w, r, GetGlobal())
+	})
+}
+
+// MetricsHandler 返回输出全局 logger 日志计数的 http.Handler，始终作用于当前的全局 logger
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveMetrics(w, r, GetGlobal())
+	})
+}
+
+// nopRoot 全局 logger 未初始化时 Module 使用的 logger，不输出任何日志
+var nopRoot = sync.OnceValue(func() *Logger {
+	logger, err := New(WithFile(false, "", ""), WithConsole(false, false), WithCore(zapcore.NewNopCore()))
+	if err != nil {
+		panic(err)
+	}
+	return logger
+})
+
+// Module 返回当前全局 logger 下名为 name 的子 logger，见 Logger.Module。
+// 未初始化时返回不输出日志的 logger；返回的 logger 绑定在当前的全局 logger 上，SetGlobal 后不会迁移
+func Module(name string) *Logger {
+	logger := GetGlobal()
+	if logger == nil {
+		logger = nopRoot()
+	}
+	return logger.Module(name)
+}
+
+// OnEntry 在全局 logger 上注册回调，见 Logger.OnEntry。回调绑定在当前的全局 logger 上，SetGlobal 后不会迁移
+func OnEntry(minLevel zapcore.Level, fn func(zapcore.Entry, []zapcore.Field)) (func(), error) {
+	logger := GetGlobal()
+	if logger == nil {
+		return nil, fmt.Errorf("logger not initialized")
+	}
+	return logger.OnEntry(minLevel, fn), nil
+}
+
+// SetModuleLevel 在运行期设置全局日志的模块级别覆盖
+func SetModuleLevel(pattern, level string) error {
+	logger := GetGlobal()
+	if logger == nil {
+		return fmt.Errorf("logger not initialized")
+	}
+	return logger.SetModuleLevel(pattern, level)
+}
@@ -0,0 +1,155 @@
+package logger_test
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/constellation39/framework/logger"
+	"github.com/constellation39/framework/logger/loggertest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestHelpersReportCallSite(t *testing.T) {
+	r := loggertest.Install(t)
+	ctx := logger.WithFields(context.Background(), zap.String("request_id", "r1"))
+
+	// 每个调用写在单独一行，期望的调用位置即该函数字面量所在的行
+	calls := []struct {
+		name string
+		fn   func()
+	}{
+		{"Info", func() { logger.Info("msg") }},
+		{"Infof", func() { logger.Infof("msg %d", 1) }},
+		{"L().Info", func() { logger.L().Info("msg") }},
+		{"S().Infof", func() { logger.S().Infof("msg %d", 1) }},
+		{"InfoCtx", func() { logger.InfoCtx(ctx, "msg") }},
+		{"Ctx().Info", func() { logger.Ctx(ctx).Info("msg") }},
+		{"FromContext().Info", func() { logger.FromContext(ctx).Info("msg") }},
+	}
+
+	for _, c := range calls {
+		t.Run(c.name, func(t *testing.T) {
+			r.Reset()
+			c.fn()
+
+			entries := r.Entries()
+			if len(entries) != 1 {
+				t.Fatalf("got %d entries, want 1", len(entries))
+			}
+			file, line := runtime.FuncForPC(reflect.ValueOf(c.fn).Pointer()).FileLine(reflect.ValueOf(c.fn).Pointer())
+			got := entries[0].Caller
+			if !got.Defined || filepath.Base(got.File) != filepath.Base(file) || got.Line != line {
+				t.Errorf("caller = %s, want %s:%d", got.String(), filepath.Base(file), line)
+			}
+		})
+	}
+}
+
+func TestModule(t *testing.T) {
+	// 未初始化时返回不输出日志的 logger
+	logger.Module("early").Info("dropped")
+
+	r := loggertest.Install(t, logger.WithLevel("info"), logger.WithModuleLevel("db", "debug"))
+	db := logger.Module("db")
+	db.Debug("query")
+	db.Module("pool").Debug("acquire")
+	logger.Module("http").Debug("hidden")
+
+	// 返回的 *Logger 可以调整模块级别
+	if err := db.SetModuleLevel("http", "debug"); err != nil {
+		t.Fatal(err)
+	}
+	logger.Module("http").Debug("request")
+
+	var got []string
+	for _, e := range r.Entries() {
+		got = append(got, e.LoggerName+":"+e.Message)
+	}
+	want := []string{"db:query", "db.pool:acquire", "http:request"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("entries = %q, want %q", got, want)
+	}
+}
+
+// discardCore 将日志编码后丢弃，用于比较编码以外的开销
+func discardCore() zapcore.Core {
+	enc := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	return zapcore.NewCore(enc, zapcore.AddSync(io.Discard), zapcore.DebugLevel)
+}
+
+// installDiscard 将只有 discardCore 输出的 logger 设为全局 logger
+func installDiscard(b *testing.B) {
+	b.Helper()
+	l, err := logger.New(
+		logger.WithLevel("debug"),
+		logger.WithFile(false, "", ""),
+		logger.WithConsole(false, false),
+		logger.WithCore(discardCore()),
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+	prev := logger.GetGlobal()
+	logger.SetGlobal(l)
+	b.Cleanup(func() {
+		logger.SetGlobal(prev)
+		_ = l.Close()
+	})
+}
+
+// BenchmarkZap 作为基准的 *zap.Logger，与全局 logger 使用相同的编码器与输出
+func BenchmarkZap(b *testing.B) {
+	l := zap.New(discardCore(), zap.AddCaller())
+	b.ReportAllocs()
+	for b.Loop() {
+		l.Info("msg", zap.Int("n", 1))
+	}
+}
+
+// BenchmarkLogger 直接调用 Logger 的方法，与便捷方法的差值即便捷方法本身的开销
+func BenchmarkLogger(b *testing.B) {
+	installDiscard(b)
+	l := logger.GetGlobal()
+	b.ReportAllocs()
+	for b.Loop() {
+		l.Info("msg", zap.Int("n", 1))
+	}
+}
+
+func BenchmarkInfo(b *testing.B) {
+	installDiscard(b)
+	b.ReportAllocs()
+	for b.Loop() {
+		logger.Info("msg", zap.Int("n", 1))
+	}
+}
+
+func BenchmarkLInfo(b *testing.B) {
+	installDiscard(b)
+	b.ReportAllocs()
+	for b.Loop() {
+		logger.L().Info("msg", zap.Int("n", 1))
+	}
+}
+
+func BenchmarkInfof(b *testing.B) {
+	installDiscard(b)
+	b.ReportAllocs()
+	for b.Loop() {
+		logger.Infof("msg %d", 1)
+	}
+}
+
+func BenchmarkInfoCtx(b *testing.B) {
+	installDiscard(b)
+	ctx := logger.WithFields(context.Background(), zap.String("request_id", "r1"))
+	b.ReportAllocs()
+	for b.Loop() {
+		logger.InfoCtx(ctx, "msg", zap.Int("n", 1))
+	}
+}
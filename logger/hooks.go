@@ -0,0 +1,205 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// hookQueueSize 每个回调的队列容量，队列满时丢弃新日志
+const hookQueueSize = 1024
+
+// hookItem 待回调的一条日志
+type hookItem struct {
+	ent    zapcore.Entry
+	fields []zapcore.Field
+}
+
+// entryHook 一个回调，在独立的协程中按顺序执行
+type entryHook struct {
+	min    zapcore.Level
+	fn     func(zapcore.Entry, []zapcore.Field)
+	queue  chan hookItem
+	done   chan struct{}
+	exited chan struct{}
+	once   sync.Once
+}
+
+func (h *entryHook) run() {
+	defer close(h.exited)
+	for {
+		select {
+		case it := <-h.queue:
+			h.call(it)
+		case <-h.done:
+			// 执行完已入队的日志
+			for {
+				select {
+				case it := <-h.queue:
+					h.call(it)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (h *entryHook) call(it hookItem) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "[logger] entry hook panic: %v\n", r)
+		}
+	}()
+	h.fn(it.ent, it.fields)
+}
+
+// stop 停止回调，已入队的日志执行完后返回
+func (h *entryHook) stop() {
+	h.once.Do(func() {
+		close(h.done)
+	})
+	<-h.exited
+}
+
+// entryHooks 已注册的回调，跨配置重新加载保留
+type entryHooks struct {
+	mu      sync.Mutex
+	list    atomic.Pointer[[]*entryHook]
+	min     atomic.Int32 // 所有回调中最低的级别，无回调时为 InvalidLevel
+	dropped atomic.Uint64
+}
+
+func newEntryHooks() *entryHooks {
+	h := &entryHooks{}
+	h.min.Store(int32(zapcore.InvalidLevel))
+	return h
+}
+
+// add 注册回调并返回取消函数
+func (h *entryHooks) add(min zapcore.Level, fn func(zapcore.Entry, []zapcore.Field)) func() {
+	hook := &entryHook{
+		min:    min,
+		fn:     fn,
+		queue:  make(chan hookItem, hookQueueSize),
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+	go hook.run()
+
+	h.mu.Lock()
+	h.update(append(h.snapshot(), hook))
+	h.mu.Unlock()
+
+	return func() {
+		h.remove(hook)
+		hook.stop()
+	}
+}
+
+func (h *entryHooks) remove(hook *entryHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	old := h.snapshot()
+	list := make([]*entryHook, 0, len(old))
+	for _, other := range old {
+		if other != hook {
+			list = append(list, other)
+		}
+	}
+	h.update(list)
+}
+
+func (h *entryHooks) snapshot() []*entryHook {
+	if p := h.list.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// update 替换回调列表并更新最低级别，调用方需持有锁
+func (h *entryHooks) update(list []*entryHook) {
+	min := zapcore.InvalidLevel
+	for _, hook := range list {
+		if min == zapcore.InvalidLevel || hook.min < min {
+			min = hook.min
+		}
+	}
+	h.list.Store(&list)
+	h.min.Store(int32(min))
+}
+
+// enabled 判断是否有回调关注该级别
+func (h *entryHooks) enabled(lvl zapcore.Level) bool {
+	min := zapcore.Level(h.min.Load())
+	return min != zapcore.InvalidLevel && lvl >= min
+}
+
+// dispatch 将日志放入关注该级别的回调队列，队列满时丢弃，不阻塞写入
+func (h *entryHooks) dispatch(ent zapcore.Entry, fields []zapcore.Field) {
+	for _, hook := range h.snapshot() {
+		if ent.Level < hook.min {
+			continue
+		}
+		select {
+		case hook.queue <- hookItem{ent: ent, fields: fields}:
+		default:
+			h.dropped.Add(1)
+		}
+	}
+}
+
+// close 停止所有回调，已入队的日志执行完后返回
+func (h *entryHooks) close() {
+	h.mu.Lock()
+	list := h.snapshot()
+	h.update(nil)
+	h.mu.Unlock()
+
+	for _, hook := range list {
+		hook.stop()
+	}
+}
+
+// hookCore 将日志分发给回调，位于脱敏之后，回调收到的是脱敏后的字段
+type hookCore struct {
+	zapcore.Core
+	hooks  *entryHooks
+	fields []zapcore.Field // With 添加的字段
+}
+
+func (c *hookCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &hookCore{Core: c.Core.With(fields), hooks: c.hooks, fields: merged}
+}
+
+func (c *hookCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *hookCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if c.hooks.enabled(ent.Level) {
+		all := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+		all = append(all, c.fields...)
+		all = append(all, fields...)
+		c.hooks.dispatch(ent, all)
+	}
+	return writeChecked(c.Core, ent, fields)
+}
+
+// OnEntry 注册回调，级别不低于 minLevel 且通过级别过滤、采样与限流的日志会异步传给 fn，不阻塞写入。
+// 每个回调在独立的协程中按顺序执行，队列满时丢弃（计入 logger_hook_dropped_entries_total）。
+// fields 包含 With 添加的字段，已脱敏；其中引用的对象在记录后不应再被修改。
+// 返回的函数用于取消注册，会等待已入队的日志执行完，不能在 fn 中调用；Close 时所有回调自动停止
+func (l *Logger) OnEntry(minLevel zapcore.Level, fn func(zapcore.Entry, []zapcore.Field)) func() {
+	return l.sinks.hooks.add(minLevel, fn)
+}
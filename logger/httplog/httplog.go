@@ -0,0 +1,220 @@
+// Package httplog 提供基于 logger 包的 net/http 中间件。
+//
+// 中间件为每个请求沿用或生成请求 ID（默认 X-Request-ID 头）并写回响应头，
+// 将带请求 ID 的 logger 存入请求 context，handler 中通过 logger.Ctx(r.Context()) 取得；
+// 请求结束时按状态码选择级别记录方法、路由、状态码、字节数、耗时与客户端地址，并恢复 handler 中的 panic。
+//
+//	mux := http.NewServeMux()
+//	mux.HandleFunc("GET /users/{id}", getUser)
+//	http.ListenAndServe(":8080", httplog.Middleware(httplog.WithSkipPaths("/healthz"))(mux))
+package httplog
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/constellation39/framework/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// RequestIDHeader 默认的请求 ID 请求头
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDKey 日志中请求 ID 字段的 key
+	RequestIDKey = "request_id"
+)
+
+// maxRequestIDLen 沿用的请求 ID 最大长度，超出或包含不可见字符时重新生成
+const maxRequestIDLen = 128
+
+// requestIDKey context 中存放请求 ID 的 key
+type requestIDKey struct{}
+
+// RequestID 返回 ctx 中的请求 ID，不在中间件内时返回空字符串
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Middleware 返回记录访问日志的中间件
+func Middleware(opts ...Option) func(http.Handler) http.Handler {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+	return func(next http.Handler) http.Handler {
+		return &handler{next: next, opts: o}
+	}
+}
+
+// Handler 使用中间件包装 next
+func Handler(next http.Handler, opts ...Option) http.Handler {
+	return Middleware(opts...)(next)
+}
+
+type handler struct {
+	next http.Handler
+	opts *options
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	o := h.opts
+
+	// 请求 ID
+	var id string
+	if o.trustHeader {
+		id = r.Header.Get(o.header)
+		if !validRequestID(id) {
+			id = ""
+		}
+	}
+	if id == "" {
+		id = o.generateID()
+	}
+	w.Header().Set(o.header, id)
+
+	// 请求范围的 logger
+	base := o.logger
+	if base == nil {
+		base = logger.L()
+	}
+	ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+	if tp := r.Header.Get("traceparent"); tp != "" {
+		ctx = logger.WithTraceparent(ctx, tp)
+	}
+	ctx = logger.WithContext(ctx, base.With(zap.String(RequestIDKey, id)))
+	req := r.WithContext(ctx)
+
+	rw := &responseWriter{ResponseWriter: w}
+	completed := false
+	defer func() {
+		if !completed && o.recover {
+			if p := recover(); p != nil {
+				if p == http.ErrAbortHandler {
+					// 约定用于中止响应，交给 net/http 处理
+					panic(p)
+				}
+				// 堆栈已单独记录，调用位置为本文件，均不输出
+				logger.Ctx(ctx).WithOptions(zap.WithCaller(false), zap.AddStacktrace(zapcore.InvalidLevel)).Error("panic recovered",
+					zap.String("panic", fmt.Sprint(p)),
+					zap.Stack("stack"),
+				)
+				if !rw.wroteHeader {
+					http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+			}
+		}
+		if !completed && !rw.wroteHeader {
+			rw.status = http.StatusInternalServerError
+		}
+		if !o.skipped(req) {
+			h.access(req, rw, time.Since(start))
+		}
+	}()
+
+	h.next.ServeHTTP(rw, req)
+	completed = true
+}
+
+// access 记录访问日志，路由取自 ServeMux 匹配的模式
+func (h *handler) access(r *http.Request, rw *responseWriter, latency time.Duration) {
+	status := rw.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	l := logger.Ctx(r.Context()).WithOptions(zap.WithCaller(false))
+	ce := l.Check(h.opts.level(status), "http request")
+	if ce == nil {
+		return
+	}
+
+	fields := make([]zap.Field, 0, 9)
+	fields = append(fields,
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+	)
+	if r.Pattern != "" {
+		fields = append(fields, zap.String("route", r.Pattern))
+	}
+	fields = append(fields,
+		zap.Int("status", status),
+		zap.Int64("bytes", rw.bytes),
+		zap.Duration("latency", latency),
+		zap.String("remote_addr", r.RemoteAddr),
+	)
+	if ua := r.UserAgent(); ua != "" {
+		fields = append(fields, zap.String("user_agent", ua))
+	}
+	ce.Write(fields...)
+}
+
+// validRequestID 判断请求中携带的请求 ID 是否可沿用：非空、不过长且只含可见 ASCII 字符
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// responseWriter 记录状态码与写出的字节数
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	// 1xx 为中间响应，不是最终状态码
+	if !w.wroteHeader && (status >= 200 || status == http.StatusSwitchingProtocols) {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.status = http.StatusOK
+		w.wroteHeader = true
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush 实现 http.Flusher
+func (w *responseWriter) Flush() {
+	if !w.wroteHeader {
+		w.status = http.StatusOK
+		w.wroteHeader = true
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack 实现 http.Hijacker
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil && !w.wroteHeader {
+		w.status = http.StatusSwitchingProtocols
+		w.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap 供 http.ResponseController 访问底层 ResponseWriter
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
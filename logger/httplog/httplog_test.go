@@ -0,0 +1,38 @@
+package httplog_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/constellation39/framework/logger/httplog"
+	"github.com/constellation39/framework/logger/loggertest"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRecoveredPanicHasSingleStack(t *testing.T) {
+	r := loggertest.New(t)
+	h := httplog.Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}), httplog.WithLogger(r.Logger.Logger))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rec.Code)
+	}
+
+	entries := r.Find(zapcore.ErrorLevel, "panic recovered")
+	if len(entries) != 1 {
+		t.Fatalf("got %d panic entries, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.Stack != "" {
+		t.Errorf("entry has an extra stacktrace: %s", e.Stack)
+	}
+	stack, _ := e.ContextMap()["stack"].(string)
+	if !strings.Contains(stack, "httplog_test.TestRecoveredPanicHasSingleStack") {
+		t.Errorf("stack field = %q", stack)
+	}
+}
@@ -0,0 +1,139 @@
+package httplog
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// options 中间件配置
+type options struct {
+	logger       *zap.Logger
+	header       string
+	trustHeader  bool
+	generateID   func() string
+	skipPaths    map[string]bool
+	skipPrefixes []string
+	skip         func(*http.Request) bool
+	level        func(status int) zapcore.Level
+	recover      bool
+}
+
+// Option 配置选项
+type Option func(*options)
+
+func defaultOptions() *options {
+	return &options{
+		header:      RequestIDHeader,
+		trustHeader: true,
+		generateID:  NewRequestID,
+		skipPaths:   make(map[string]bool),
+		level:       LevelByStatus,
+		recover:     true,
+	}
+}
+
+// WithLogger 设置写日志的 logger，默认每次请求时取 logger.L()
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithRequestIDHeader 设置传递请求 ID 的请求头，默认 X-Request-ID
+func WithRequestIDHeader(header string) Option {
+	return func(o *options) {
+		if header != "" {
+			o.header = header
+		}
+	}
+}
+
+// WithTrustRequestID 设置是否沿用请求中携带的请求 ID，默认沿用；不可信的入口处应关闭
+func WithTrustRequestID(trust bool) Option {
+	return func(o *options) {
+		o.trustHeader = trust
+	}
+}
+
+// WithRequestIDGenerator 设置请求 ID 生成函数，默认 NewRequestID
+func WithRequestIDGenerator(fn func() string) Option {
+	return func(o *options) {
+		if fn != nil {
+			o.generateID = fn
+		}
+	}
+}
+
+// WithSkipPaths 设置不记录访问日志的路径，如 "/healthz"；以 "*" 结尾时按前缀匹配，如 "/debug/*"。
+// 被跳过的请求仍会分配请求 ID 与 logger，panic 仍会被恢复
+func WithSkipPaths(paths ...string) Option {
+	return func(o *options) {
+		for _, p := range paths {
+			if prefix, ok := strings.CutSuffix(p, "*"); ok {
+				o.skipPrefixes = append(o.skipPrefixes, prefix)
+			} else {
+				o.skipPaths[p] = true
+			}
+		}
+	}
+}
+
+// WithSkip 设置判断是否跳过访问日志的函数，与 WithSkipPaths 同时生效
+func WithSkip(fn func(*http.Request) bool) Option {
+	return func(o *options) {
+		o.skip = fn
+	}
+}
+
+// WithLevel 设置按状态码选择日志级别的函数，默认 LevelByStatus
+func WithLevel(fn func(status int) zapcore.Level) Option {
+	return func(o *options) {
+		if fn != nil {
+			o.level = fn
+		}
+	}
+}
+
+// WithRecover 设置是否恢复 handler 中的 panic，默认恢复
+func WithRecover(enabled bool) Option {
+	return func(o *options) {
+		o.recover = enabled
+	}
+}
+
+// LevelByStatus 5xx 为 Error，4xx 为 Warn，其余为 Info
+func LevelByStatus(status int) zapcore.Level {
+	switch {
+	case status >= 500:
+		return zapcore.ErrorLevel
+	case status >= 400:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+// NewRequestID 生成 32 位十六进制的随机请求 ID
+func NewRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// skipped 判断请求是否跳过访问日志
+func (o *options) skipped(r *http.Request) bool {
+	if o.skipPaths[r.URL.Path] {
+		return true
+	}
+	for _, prefix := range o.skipPrefixes {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return true
+		}
+	}
+	return o.skip != nil && o.skip(r)
+}
@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Level 返回当前生效的日志级别
+func (l *Logger) Level() zapcore.Level {
+	return l.level.Level()
+}
+
+// SetLevel 在运行期调整日志级别，立即对所有派生 logger 生效
+func (l *Logger) SetLevel(level zapcore.Level) {
+	l.level.SetLevel(level)
+}
+
+// SetLevelString 以字符串形式调整日志级别，如 "debug"、"warn"
+func (l *Logger) SetLevelString(level string) error {
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	l.level.SetLevel(lvl)
+	return nil
+}
+
+// LevelHandler 返回调整日志级别的 http.Handler
+//
+//	GET  返回 {"level":"info"}
+//	PUT  请求体 {"level":"debug"}，返回调整后的级别
+func (l *Logger) LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveLevel(w, r, l)
+	})
+}
+
+// levelPayload 级别接口的请求/响应体
+type levelPayload struct {
+	Level string `json:"level"`
+}
+
+// levelError 级别接口的错误响应体
+type levelError struct {
+	Error string `json:"error"`
+}
+
+// serveLevel 处理级别查询与调整请求
+func serveLevel(w http.ResponseWriter, r *http.Request, l *Logger) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+
+	if l == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = enc.Encode(levelError{Error: "logger not initialized"})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		_ = enc.Encode(levelPayload{Level: l.Level().String()})
+
+	case http.MethodPut:
+		var req levelPayload
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = enc.Encode(levelError{Error: "invalid request body: " + err.Error()})
+			return
+		}
+		if err := l.SetLevelString(req.Level); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = enc.Encode(levelError{Error: err.Error()})
+			return
+		}
+		_ = enc.Encode(levelPayload{Level: l.Level().String()})
+
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = enc.Encode(levelError{Error: "only GET and PUT are supported"})
+	}
+}
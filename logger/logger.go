@@ -3,13 +3,9 @@ package logger
 import (
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
 	"strings"
-	"sync"
 	"time"
 
-	rotatelogs "github.com/lestrrat-go/file-rotatelogs"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -18,10 +14,11 @@ import (
 type Logger struct {
 	*zap.Logger
 	sugar      *zap.SugaredLogger
-	rotateLog  io.Closer
-	config     *Config
-	callerOnce sync.Once
-	callerPath string
+	level      zap.AtomicLevel
+	stackLevel zap.AtomicLevel
+	modules    *moduleLevels
+	sinks      *sinkSwitch
+	owner      bool // 根 logger 负责关闭输出
 }
 
 // Config 日志配置
@@ -31,31 +28,82 @@ type Config struct {
 	Encoding    string `json:"encoding" yaml:"encoding"`       // 编码格式: json, console
 	Environment string `json:"environment" yaml:"environment"` // 环境: development, production
 
+	// 模块级别覆盖，如 {"db.*": "debug", "http": "warn"}
+	ModuleLevels map[string]string `json:"module_levels" yaml:"module_levels"`
+
 	// 文件配置
 	EnableFile     bool   `json:"enable_file" yaml:"enable_file"`           // 是否启用文件输出
 	LogDir         string `json:"log_dir" yaml:"log_dir"`                   // 日志目录
 	Filename       string `json:"filename" yaml:"filename"`                 // 日志文件名前缀
+	FilePattern    string `json:"file_pattern" yaml:"file_pattern"`         // 文件名中间部分的模式，默认 %Y%m%d.%N
 	MaxAge         int    `json:"max_age" yaml:"max_age"`                   // 日志保留天数
 	RotationTime   int    `json:"rotation_time" yaml:"rotation_time"`       // 轮转时间(小时)
 	RotationSize   int64  `json:"rotation_size" yaml:"rotation_size"`       // 轮转大小(MB)
 	RotationCount  uint   `json:"rotation_count" yaml:"rotation_count"`     // 保留文件数量
+	MaxTotalSize   int64  `json:"max_total_size" yaml:"max_total_size"`     // 日志总大小上限(MB)
 	CompressOldLog bool   `json:"compress_old_log" yaml:"compress_old_log"` // 是否压缩旧日志
 
+	// 错误日志文件配置，启用后指定级别的日志额外写入 <Filename>.error.log
+	EnableErrorFile    bool     `json:"enable_error_file" yaml:"enable_error_file"`       // 是否启用错误日志文件
+	ErrorFileLevels    []string `json:"error_file_levels" yaml:"error_file_levels"`       // 写入错误日志文件的级别，默认 warn 及以上
+	ErrorMaxAge        int      `json:"error_max_age" yaml:"error_max_age"`               // 错误日志保留天数，为 0 时沿用 MaxAge
+	ErrorRotationCount uint     `json:"error_rotation_count" yaml:"error_rotation_count"` // 错误日志保留文件数量，为 0 时沿用 RotationCount
+
 	// 控制台配置
 	EnableConsole bool `json:"enable_console" yaml:"enable_console"` // 是否启用控制台输出
 	ColorConsole  bool `json:"color_console" yaml:"color_console"`   // 控制台是否彩色输出
 
+	// 多输出配置，非空时取代上面的文件与控制台配置（文件输出未设置的轮转参数仍沿用上面的配置）
+	Outputs []OutputConfig `json:"outputs" yaml:"outputs"`
+
+	// 脱敏配置，对所有输出统一生效
+	Redaction RedactionConfig `json:"redaction" yaml:"redaction"`
+
 	// 高级配置
 	EnableStacktrace bool   `json:"enable_stacktrace" yaml:"enable_stacktrace"` // 是否启用堆栈跟踪
 	StacktraceLevel  string `json:"stacktrace_level" yaml:"stacktrace_level"`   // 堆栈跟踪级别
-	MaxStackFrames   int    `json:"max_stack_frames" yaml:"max_stack_frames"`   // 最大堆栈帧数
+	MaxStackFrames   int    `json:"max_stack_frames" yaml:"max_stack_frames"`   // 最大堆栈帧数，仅 text 格式使用
 	CallerSkip       int    `json:"caller_skip" yaml:"caller_skip"`             // 调用者跳过层数
 	EnableSampling   bool   `json:"enable_sampling" yaml:"enable_sampling"`     // 是否启用采样
 	SamplingInitial  int    `json:"sampling_initial" yaml:"sampling_initial"`   // 采样初始值
 	SamplingAfter    int    `json:"sampling_after" yaml:"sampling_after"`       // 采样之后值
+
+	// 堆栈格式，可被各输出的 Encoder.StackFormat 覆盖
+	StackFormat     string   `json:"stack_format" yaml:"stack_format"`           // text（默认）或 structured
+	StackFilter     []string `json:"stack_filter" yaml:"stack_filter"`           // structured 格式下丢弃的帧所属的包，按包路径前缀匹配；未设置时为 runtime、go.uber.org/zap 与本日志包
+	StackHeadFrames int      `json:"stack_head_frames" yaml:"stack_head_frames"` // structured 格式下保留开头的帧数，默认 8
+	StackTailFrames int      `json:"stack_tail_frames" yaml:"stack_tail_frames"` // structured 格式下保留末尾的帧数，默认 2；两者均为 0 时不省略
+
+	// 调用位置格式，可被各输出的 Encoder.CallerFormat 覆盖
+	CallerFormat       string   `json:"caller_format" yaml:"caller_format"`               // short, full, module, trim；为空时控制台为 module，其余为 short
+	CallerTrimPrefixes []string `json:"caller_trim_prefixes" yaml:"caller_trim_prefixes"` // trim 格式去掉的包路径前缀，如 ["github.com/acme/"]
+	CallerFunction     bool     `json:"caller_function" yaml:"caller_function"`           // 是否输出调用位置的函数名
+
+	// 限流配置，按消息与指定字段分组，超出预算的日志被抑制并在窗口结束时汇总
+	RateLimit RateLimitConfig `json:"rate_limit" yaml:"rate_limit"`
+
+	// 标准库集成
+	SlogDefault    bool   `json:"slog_default" yaml:"slog_default"`         // SetGlobal 时是否同时调用 slog.SetDefault
+	RedirectStdLog bool   `json:"redirect_std_log" yaml:"redirect_std_log"` // Init 时是否将标准库 log 的输出重定向到本日志
+	StdLogLevel    string `json:"std_log_level" yaml:"std_log_level"`       // 标准库 log 输出的级别，默认 info
+
+	// 崩溃处理，用于 Go 与 Recover 捕获的 panic
+	CrashReport     bool   `json:"crash_report" yaml:"crash_report"`           // 是否写入崩溃报告（所有协程堆栈与构建信息），默认关闭
+	CrashDir        string `json:"crash_dir" yaml:"crash_dir"`                 // 崩溃报告目录，为空时为 LogDir
+	CrashMaxReports int    `json:"crash_max_reports" yaml:"crash_max_reports"` // 保留的崩溃报告数量，超出时删除最旧的报告，0 表示不限制
+	CrashRepanic    bool   `json:"crash_repanic" yaml:"crash_repanic"`         // 记录后是否重新 panic，否则该协程结束而进程继续运行
+
+	// 异步写入配置
+	EnableAsync    bool   `json:"enable_async" yaml:"enable_async"`         // 是否启用异步写入
+	AsyncQueueSize int    `json:"async_queue_size" yaml:"async_queue_size"` // 队列容量(条)
+	AsyncOverflow  string `json:"async_overflow" yaml:"async_overflow"`     // 队列满时的策略: block, drop_newest, drop_oldest, drop_below
+	AsyncDropLevel string `json:"async_drop_level" yaml:"async_drop_level"` // drop_below 策略下，队列满时丢弃低于该级别的日志
+
+	optErr  error                  // 选项执行中的错误（读取配置文件、解析环境变量等），由 New 返回
+	sources map[string]valueSource // 字段来源，见 Sources
+	cores   []zapcore.Core         // WithCore 追加的自定义输出
 }
 
-// 默认配置
 // 默认配置
 func defaultConfig() *Config {
 	return &Config{
@@ -65,10 +113,12 @@ func defaultConfig() *Config {
 		EnableFile:       true,
 		LogDir:           "logs",
 		Filename:         "app",
-		MaxAge:           0,        // 设为 0，不使用时间清理
+		FilePattern:      "%Y%m%d.%N",
+		MaxAge:           0, // 设为 0，不使用时间清理
 		RotationTime:     24,
 		RotationSize:     100,
-		RotationCount:    10,       // 使用数量清理，保留 10 个文件
+		RotationCount:    10, // 使用数量清理，保留 10 个文件
+		MaxTotalSize:     0,  // 设为 0，不限制总大小
 		CompressOldLog:   false,
 		EnableConsole:    true,
 		ColorConsole:     true,
@@ -79,10 +129,22 @@ func defaultConfig() *Config {
 		EnableSampling:   false,
 		SamplingInitial:  100,
 		SamplingAfter:    100,
+		EnableAsync:      false,
+		AsyncQueueSize:   8192,
+		AsyncOverflow:    OverflowBlock,
+		AsyncDropLevel:   "warn",
+		StdLogLevel:      "info",
+		CrashMaxReports:  10,
+		StackFormat:      StackText,
+		StackHeadFrames:  defaultStackHeadFrames,
+		StackTailFrames:  defaultStackTailFrames,
+		RateLimit: RateLimitConfig{
+			Window:  defaultRateLimitWindow,
+			MaxKeys: defaultRateLimitMaxKeys,
+		},
 	}
 }
 
-
 // New 创建新的日志实例
 func New(opts ...Option) (*Logger, error) {
 	cfg := defaultConfig()
@@ -92,18 +154,34 @@ func New(opts ...Option) (*Logger, error) {
 		opt(cfg)
 	}
 
-	// 根据环境自动调整默认值
+	// 配置文件、环境变量等读取失败
+	if cfg.optErr != nil {
+		return nil, cfg.optErr
+	}
+
+	applyEnvironment(cfg)
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return newLogger(cfg)
+}
+
+// applyEnvironment 根据环境自动调整默认值
+func applyEnvironment(cfg *Config) {
 	if cfg.Environment == "production" {
 		if cfg.Encoding == "console" {
 			cfg.Encoding = "json"
+			cfg.recordSourceByPath("encoding", SourceEnvironment)
 		}
 		if !cfg.EnableSampling {
 			cfg.EnableSampling = true
+			cfg.recordSourceByPath("enable_sampling", SourceEnvironment)
+		}
+		if cfg.ColorConsole {
+			cfg.ColorConsole = false
+			cfg.recordSourceByPath("color_console", SourceEnvironment)
 		}
-		cfg.ColorConsole = false
 	}
-
-	return newLogger(cfg)
 }
 
 // MustNew 创建日志实例，失败则 panic
@@ -123,180 +201,150 @@ func newLogger(cfg *Config) (*Logger, error) {
 	}
 
 	// 解析堆栈跟踪级别
-	stackLevel, err := parseLevel(cfg.StacktraceLevel)
+	stackLevel, err := stacktraceLevel(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("invalid stacktrace level %s: %w", cfg.StacktraceLevel, err)
+		return nil, err
 	}
 
-	// 构建 cores
-	cores := make([]zapcore.Core, 0, 2)
-	var rotateLog io.Closer
+	// 可在运行期调整的日志级别
+	atomicLevel := zap.NewAtomicLevelAt(level)
+	atomicStackLevel := zap.NewAtomicLevelAt(stackLevel)
 
-	// 文件输出
-	if cfg.EnableFile {
-		fileCore, rl, err := buildFileCore(cfg, level)
-		if err != nil {
-			return nil, fmt.Errorf("failed to build file core: %w", err)
-		}
-		cores = append(cores, fileCore)
-		rotateLog = rl
-	}
-
-	// 控制台输出
-	if cfg.EnableConsole {
-		consoleCore := buildConsoleCore(cfg, level)
-		cores = append(cores, consoleCore)
+	// 模块级别覆盖
+	modules, err := newModuleLevels(cfg.ModuleLevels)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(cores) == 0 {
-		return nil, fmt.Errorf("at least one output (file or console) must be enabled")
+	// 构建输出，重新加载配置时整体替换
+	m, hooks := &metrics{}, newEntryHooks()
+	set, err := buildSinks(cfg, m, hooks)
+	if err != nil {
+		return nil, err
 	}
+	sinks := newSinkSwitch(set, m, hooks)
 
-	// 组合多个 core
-	core := zapcore.NewTee(cores...)
-
-	// 包装堆栈截断
-	if cfg.EnableStacktrace && cfg.MaxStackFrames > 0 {
-		core = &stackTrimCore{
-			Core:      core,
-			maxFrames: cfg.MaxStackFrames,
-		}
+	// 按全局级别与模块级别过滤
+	core := &levelCore{
+		Core:    &sinkCore{sinks: sinks},
+		base:    atomicLevel,
+		modules: modules,
 	}
 
 	// 构建选项
 	zapOpts := []zap.Option{
 		zap.AddCaller(),
 		zap.AddCallerSkip(cfg.CallerSkip),
-	}
-
-	if cfg.EnableStacktrace {
-		zapOpts = append(zapOpts, zap.AddStacktrace(stackLevel))
-	}
-
-	// 添加采样
-	if cfg.EnableSampling {
-		zapOpts = append(zapOpts, zap.WrapCore(func(c zapcore.Core) zapcore.Core {
-			return zapcore.NewSamplerWithOptions(
-				c,
-				time.Second,
-				cfg.SamplingInitial,
-				cfg.SamplingAfter,
-			)
-		}))
+		zap.AddStacktrace(atomicStackLevel),
 	}
 
 	// 创建 logger
 	zapLogger := zap.New(core, zapOpts...)
 
 	logger := &Logger{
-		Logger:    zapLogger,
-		sugar:     zapLogger.Sugar(),
-		rotateLog: rotateLog,
-		config:    cfg,
+		Logger:     zapLogger,
+		sugar:      zapLogger.Sugar(),
+		level:      atomicLevel,
+		stackLevel: atomicStackLevel,
+		modules:    modules,
+		sinks:      sinks,
+		owner:      true,
 	}
 
 	return logger, nil
 }
 
-// buildFileCore 构建文件输出 core
-func buildFileCore(cfg *Config, level zapcore.Level) (zapcore.Core, io.Closer, error) {
-	// 创建日志目录
-	if err := os.MkdirAll(cfg.LogDir, 0755); err != nil {
-		return nil, nil, fmt.Errorf("failed to create log directory: %w", err)
+// buildSinks 按配置构建全部输出，以及其上的异步、回调、脱敏、采样与限流层
+func buildSinks(cfg *Config, m *metrics, hooks *entryHooks) (*sinkSet, error) {
+	// 构建所有输出，级别区间由各输出自行控制，全局级别由外层 levelCore 统一控制
+	cores, closers, err := buildOutputs(cfg, m)
+	if err != nil {
+		return nil, err
 	}
-
-	// 构建日志文件路径
-	logPath := filepath.Join(cfg.LogDir, cfg.Filename+".%Y%m%d.log")
-	linkPath := filepath.Join(cfg.LogDir, cfg.Filename+".log")
-
-	// 配置 rotatelogs
-	rotateOpts := []rotatelogs.Option{
-		rotatelogs.WithLinkName(linkPath),
-		rotatelogs.WithRotationTime(time.Duration(cfg.RotationTime) * time.Hour),
+	for i, core := range cfg.cores {
+		cores = append(cores, m.sink(fmt.Sprintf("core[%d]", i), core, nil))
 	}
 
-	// 添加轮转大小配置
-	if cfg.RotationSize > 0 {
-		rotateOpts = append(rotateOpts, rotatelogs.WithRotationSize(cfg.RotationSize*1024*1024))
+	if len(cores) == 0 {
+		return nil, fmt.Errorf("at least one output must be enabled")
 	}
 
-	// RotationCount 和 MaxAge 二选一
-	// 优先使用 RotationCount，如果为 0 则使用 MaxAge
-	if cfg.RotationCount > 0 {
-		rotateOpts = append(rotateOpts, rotatelogs.WithRotationCount(cfg.RotationCount))
-	} else if cfg.MaxAge > 0 {
-		rotateOpts = append(rotateOpts, rotatelogs.WithMaxAge(time.Duration(cfg.MaxAge)*24*time.Hour))
-	}
+	// 组合多个 core
+	core := zapcore.NewTee(cores...)
 
-	// 创建 rotatelogs
-	logWriter, err := rotatelogs.New(logPath, rotateOpts...)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create rotatelogs: %w", err)
+	// 异步写入，队列需先于各输出关闭以便写完剩余日志
+	var queue *asyncQueue
+	if cfg.EnableAsync {
+		policy, err := parseOverflowPolicy(cfg.AsyncOverflow)
+		if err != nil {
+			_ = closeAll(closers)
+			return nil, err
+		}
+		dropLevel, err := parseLevel(cfg.AsyncDropLevel)
+		if err != nil {
+			_ = closeAll(closers)
+			return nil, fmt.Errorf("invalid async drop level %s: %w", cfg.AsyncDropLevel, err)
+		}
+		queue = newAsyncQueue(cfg.AsyncQueueSize, policy, dropLevel)
+		core = &asyncCore{Core: core, queue: queue}
+		closers = append([]io.Closer{queue}, closers...)
 	}
 
-	// 构建编码器
-	encoder := buildEncoder(cfg, false)
+	// 回调在脱敏之后，收到的是脱敏后的字段
+	core = &hookCore{Core: core, hooks: hooks}
 
-	core := zapcore.NewCore(
-		encoder,
-		zapcore.AddSync(logWriter),
-		level,
-	)
-
-	return core, logWriter, nil
-}
-
-// buildConsoleCore 构建控制台输出 core
-func buildConsoleCore(cfg *Config, level zapcore.Level) zapcore.Core {
-	encoder := buildEncoder(cfg, true)
-
-	return zapcore.NewCore(
-		encoder,
-		zapcore.AddSync(os.Stdout),
-		level,
-	)
-}
-
-// buildEncoder 构建编码器
-func buildEncoder(cfg *Config, isConsole bool) zapcore.Encoder {
-	var encoderConfig zapcore.EncoderConfig
-
-	if cfg.Environment == "production" {
-		encoderConfig = zap.NewProductionEncoderConfig()
-	} else {
-		encoderConfig = zap.NewDevelopmentEncoderConfig()
+	// 脱敏在入队前完成，异步写入时被记录的对象不会以原文进入队列
+	if cfg.Redaction.enabled() {
+		r, err := newRedactor(&cfg.Redaction)
+		if err != nil {
+			_ = closeAll(closers)
+			return nil, err
+		}
+		core = &redactCore{Core: core, r: r}
 	}
 
-	// 统一字段名
-	encoderConfig.TimeKey = "time"
-	encoderConfig.LevelKey = "level"
-	encoderConfig.NameKey = "logger"
-	encoderConfig.CallerKey = "caller"
-	encoderConfig.FunctionKey = zapcore.OmitKey
-	encoderConfig.MessageKey = "msg"
-	encoderConfig.StacktraceKey = "stacktrace"
-	encoderConfig.LineEnding = zapcore.DefaultLineEnding
-	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-	encoderConfig.EncodeDuration = zapcore.MillisDurationEncoder
-
-	// 控制台特殊配置
-	if isConsole {
-		encoderConfig.EncodeCaller = relativeCallerEncoder
-		if cfg.ColorConsole {
-			encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
-		} else {
-			encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	// 添加采样，只对通过级别过滤的日志计数
+	if cfg.EnableSampling {
+		core = zapcore.NewSamplerWithOptions(
+			core,
+			time.Second,
+			cfg.SamplingInitial,
+			cfg.SamplingAfter,
+			zapcore.SamplerHook(m.samplerHook),
+		)
+	}
+
+	// 限流在最外层，汇总日志同样经过脱敏与异步队列；需先于队列关闭以便写出剩余汇总
+	var limiter *rateLimiter
+	if cfg.RateLimit.Enabled {
+		limiter, err = newRateLimiter(&cfg.RateLimit, zapcore.DefaultClock)
+		if err != nil {
+			_ = closeAll(closers)
+			return nil, err
 		}
-	} else {
-		encoderConfig.EncodeCaller = zapcore.ShortCallerEncoder
-		encoderConfig.EncodeLevel = zapcore.LowercaseLevelEncoder
+		core = &rateLimitCore{Core: core, limiter: limiter}
+		closers = append([]io.Closer{limiter}, closers...)
 	}
 
-	// 根据编码格式创建编码器
-	if cfg.Encoding == "json" || !isConsole {
-		return zapcore.NewJSONEncoder(encoderConfig)
+	return &sinkSet{
+		core:    core,
+		closers: closers,
+		async:   queue,
+		limiter: limiter,
+		config:  cfg,
+	}, nil
+}
+
+// stacktraceLevel 返回记录堆栈的最低级别，未启用堆栈跟踪时返回不会被任何日志触发的级别
+func stacktraceLevel(cfg *Config) (zapcore.Level, error) {
+	if !cfg.EnableStacktrace {
+		return zapcore.InvalidLevel, nil
 	}
-	return zapcore.NewConsoleEncoder(encoderConfig)
+	level, err := parseLevel(cfg.StacktraceLevel)
+	if err != nil {
+		return level, fmt.Errorf("invalid stacktrace level %s: %w", cfg.StacktraceLevel, err)
+	}
+	return level, nil
 }
 
 // parseLevel 解析日志级别
@@ -321,34 +369,6 @@ func parseLevel(level string) (zapcore.Level, error) {
 	}
 }
 
-// relativeCallerEncoder 相对路径编码器
-func relativeCallerEncoder(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
-	// 只计算一次工作目录
-	l := globalLogger.Load()
-	if l == nil {
-		zapcore.ShortCallerEncoder(caller, enc)
-		return
-	}
-
-	logger := l.(*Logger)
-	logger.callerOnce.Do(func() {
-		logger.callerPath, _ = os.Getwd()
-	})
-
-	if logger.callerPath == "" {
-		zapcore.ShortCallerEncoder(caller, enc)
-		return
-	}
-
-	relPath, err := filepath.Rel(logger.callerPath, caller.File)
-	if err != nil {
-		zapcore.ShortCallerEncoder(caller, enc)
-		return
-	}
-
-	enc.AppendString(fmt.Sprintf("%s:%d", relPath, caller.Line))
-}
-
 // stackTrimCore 堆栈截断核心
 type stackTrimCore struct {
 	zapcore.Core
@@ -400,21 +420,30 @@ func (l *Logger) Sync() error {
 
 // Close 关闭日志
 func (l *Logger) Close() error {
+	// 恢复标准库 log 的输出与 slog 的默认 logger
+	restoreStdLog(l)
+	restoreSlogDefault(l)
+
 	// 先刷新缓冲区
 	if err := l.Sync(); err != nil {
 		return err
 	}
 
-	// 关闭 rotatelogs
-	if l.rotateLog != nil {
-		return l.rotateLog.Close()
+	// 关闭文件、网络连接等输出资源
+	if !l.owner {
+		return nil
 	}
+	err := l.sinks.close()
 
-	return nil
+	// 限流汇总等在关闭输出时写出，回调最后停止
+	l.sinks.hooks.close()
+	return err
 }
 
-// GetConfig 获取配置
+// GetConfig 获取配置（Level 为当前生效的级别）
 func (l *Logger) GetConfig() *Config {
-	cfg := *l.config
+	cfg := *l.sinks.current().config
+	cfg.Level = l.level.Level().String()
+	cfg.ModuleLevels = l.modules.snapshot()
 	return &cfg
 }
@@ -0,0 +1,233 @@
+// Package loggertest 提供在测试中捕获并断言日志的工具。
+//
+// New 为单个测试创建独立的 logger，可配合 t.Parallel 使用；
+// Install 额外将其设为全局 logger，使 logger.L()、logger.S() 的输出也被捕获，
+// 测试结束时恢复原来的全局 logger，因此不能与 t.Parallel 同时使用。
+package loggertest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/constellation39/framework/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// Entry 捕获的一条日志，Context 中为全部字段（含 With 添加的字段）
+type Entry = observer.LoggedEntry
+
+// Recorder 捕获日志的 logger
+type Recorder struct {
+	*logger.Logger
+	logs *observer.ObservedLogs
+}
+
+var (
+	mu        sync.Mutex
+	byTest    = make(map[testing.TB]*Recorder)
+	byLogger  = make(map[*logger.Logger]*Recorder)
+	installed = make(map[testing.TB]bool)
+)
+
+// New 创建捕获日志的 logger，默认级别为 debug 且不写文件与控制台，测试结束时自动关闭。
+// opts 在默认选项之后应用
+func New(t testing.TB, opts ...logger.Option) *Recorder {
+	t.Helper()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	base := []logger.Option{
+		logger.WithLevel("debug"),
+		logger.WithFile(false, "", ""),
+		logger.WithConsole(false, false),
+		logger.WithCore(core),
+	}
+	l, err := logger.New(append(base, opts...)...)
+	if err != nil {
+		t.Fatalf("loggertest: failed to create logger: %v", err)
+	}
+
+	r := &Recorder{Logger: l, logs: logs}
+
+	mu.Lock()
+	byTest[t] = r
+	byLogger[l] = r
+	mu.Unlock()
+
+	t.Cleanup(func() {
+		mu.Lock()
+		if byTest[t] == r {
+			delete(byTest, t)
+		}
+		delete(byLogger, l)
+		mu.Unlock()
+
+		_ = l.Close()
+	})
+	return r
+}
+
+// Install 创建捕获日志的 logger 并设为全局 logger，测试结束时恢复原来的全局 logger
+func Install(t testing.TB, opts ...logger.Option) *Recorder {
+	t.Helper()
+
+	mu.Lock()
+	if installed[t] {
+		mu.Unlock()
+		t.Fatalf("loggertest: Install called twice in the same test")
+	}
+	installed[t] = true
+	mu.Unlock()
+
+	r := New(t, opts...)
+	prev := logger.GetGlobal()
+	logger.SetGlobal(r.Logger)
+
+	// 先于 New 注册的关闭执行
+	t.Cleanup(func() {
+		logger.SetGlobal(prev)
+
+		mu.Lock()
+		delete(installed, t)
+		mu.Unlock()
+	})
+	return r
+}
+
+// Entries 返回已捕获的全部日志
+func (r *Recorder) Entries() []Entry {
+	return r.logs.All()
+}
+
+// Logs 返回底层的 observer，可使用其 Filter 系列方法
+func (r *Recorder) Logs() *observer.ObservedLogs {
+	return r.logs
+}
+
+// Reset 清空已捕获的日志
+func (r *Recorder) Reset() {
+	r.logs.TakeAll()
+}
+
+// Find 返回级别为 level、消息包含 msgSubstring 且包含全部 fields 的日志
+func (r *Recorder) Find(level zapcore.Level, msgSubstring string, fields ...zap.Field) []Entry {
+	want := encodeFields(fields)
+
+	var matched []Entry
+	for _, e := range r.logs.All() {
+		if e.Level == level && strings.Contains(e.Message, msgSubstring) && hasFields(e, want) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// RequireLogged 断言存在匹配的日志，不存在时终止测试
+func (r *Recorder) RequireLogged(t testing.TB, level zapcore.Level, msgSubstring string, fields ...zap.Field) {
+	t.Helper()
+	if len(r.Find(level, msgSubstring, fields...)) == 0 {
+		t.Fatalf("loggertest: no %s entry containing %q with fields %s\n%s",
+			level, msgSubstring, formatFields(encodeFields(fields)), r.dump())
+	}
+}
+
+// RequireNotLogged 断言不存在匹配的日志，存在时终止测试
+func (r *Recorder) RequireNotLogged(t testing.TB, level zapcore.Level, msgSubstring string, fields ...zap.Field) {
+	t.Helper()
+	if matched := r.Find(level, msgSubstring, fields...); len(matched) > 0 {
+		t.Fatalf("loggertest: unexpected %s entry containing %q: %s",
+			level, msgSubstring, formatEntry(matched[0]))
+	}
+}
+
+// RequireLogged 在 t 的 Recorder 上断言存在匹配的日志。
+// t 未通过 New/Install 创建 Recorder 时（如子测试），使用当前全局 logger 的 Recorder
+func RequireLogged(t testing.TB, level zapcore.Level, msgSubstring string, fields ...zap.Field) {
+	t.Helper()
+	lookup(t).RequireLogged(t, level, msgSubstring, fields...)
+}
+
+// RequireNotLogged 在 t 的 Recorder 上断言不存在匹配的日志，查找规则同 RequireLogged
+func RequireNotLogged(t testing.TB, level zapcore.Level, msgSubstring string, fields ...zap.Field) {
+	t.Helper()
+	lookup(t).RequireNotLogged(t, level, msgSubstring, fields...)
+}
+
+// Entries 返回 t 的 Recorder 已捕获的全部日志，查找规则同 RequireLogged
+func Entries(t testing.TB) []Entry {
+	t.Helper()
+	return lookup(t).Entries()
+}
+
+// lookup 查找 t 对应的 Recorder
+func lookup(t testing.TB) *Recorder {
+	t.Helper()
+
+	mu.Lock()
+	r, ok := byTest[t]
+	if !ok {
+		r, ok = byLogger[logger.GetGlobal()]
+	}
+	mu.Unlock()
+
+	if !ok {
+		t.Fatalf("loggertest: no recorder for this test, call loggertest.New or loggertest.Install first")
+	}
+	return r
+}
+
+// encodeFields 将字段编码为与 Entry.ContextMap 相同的形式
+func encodeFields(fields []zap.Field) map[string]interface{} {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return enc.Fields
+}
+
+func hasFields(e Entry, want map[string]interface{}) bool {
+	if len(want) == 0 {
+		return true
+	}
+	got := e.ContextMap()
+	for k, v := range want {
+		actual, ok := got[k]
+		if !ok || !reflect.DeepEqual(actual, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Recorder) dump() string {
+	entries := r.logs.All()
+	if len(entries) == 0 {
+		return "captured entries: none"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "captured entries (%d):", len(entries))
+	for _, e := range entries {
+		b.WriteString("\n  ")
+		b.WriteString(formatEntry(e))
+	}
+	return b.String()
+}
+
+func formatEntry(e Entry) string {
+	s := e.Level.CapitalString() + " " + e.Message
+	if e.LoggerName != "" {
+		s = e.Level.CapitalString() + " [" + e.LoggerName + "] " + e.Message
+	}
+	if ctx := e.ContextMap(); len(ctx) > 0 {
+		s += " " + formatFields(ctx)
+	}
+	return s
+}
+
+func formatFields(fields map[string]interface{}) string {
+	return fmt.Sprintf("%v", fields)
+}
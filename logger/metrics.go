@@ -0,0 +1,232 @@
+package logger
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// metrics 日志计数，跨配置重新加载保留
+type metrics struct {
+	entries  counterVec[entryKey] // 按级别与 logger 名
+	written  counterVec[sinkKey]  // 各输出成功写入的条数
+	failures counterVec[string]   // 各输出写入失败的条数
+	lost     counterVec[string]   // 各输出已接收但最终未送达的条数
+	sampled  counterVec[zapcore.Level]
+}
+
+// entryKey 日志条数的标签
+type entryKey struct {
+	level  zapcore.Level
+	logger string
+}
+
+// sinkKey 输出写入条数的标签
+type sinkKey struct {
+	sink  string
+	level zapcore.Level
+}
+
+// counterVec 按标签分组的计数器
+type counterVec[K comparable] struct {
+	m sync.Map // K -> *atomic.Uint64
+}
+
+func (v *counterVec[K]) inc(key K) {
+	v.add(key, 1)
+}
+
+func (v *counterVec[K]) add(key K, n uint64) {
+	c, ok := v.m.Load(key)
+	if !ok {
+		c, _ = v.m.LoadOrStore(key, new(atomic.Uint64))
+	}
+	c.(*atomic.Uint64).Add(n)
+}
+
+func (v *counterVec[K]) each(fn func(key K, n uint64)) {
+	v.m.Range(func(key, c any) bool {
+		fn(key.(K), c.(*atomic.Uint64).Load())
+		return true
+	})
+}
+
+// samplerHook 统计被采样丢弃的条数
+func (m *metrics) samplerHook(ent zapcore.Entry, dec zapcore.SamplingDecision) {
+	if dec&zapcore.LogDropped != 0 {
+		m.sampled.inc(ent.Level)
+	}
+}
+
+// sinkReporter 自行统计送达结果的输出，如带缓冲的 syslog 在实际发出或丢弃时才计数
+type sinkReporter interface {
+	reportTo(name string, m *metrics)
+}
+
+// sink 包装单个输出，统计写入与失败的条数。
+// reporter 不为 nil 时由其统计成功与丢弃的条数，这里只统计写入失败
+func (m *metrics) sink(name string, core zapcore.Core, reporter sinkReporter) zapcore.Core {
+	if reporter != nil {
+		reporter.reportTo(name, m)
+	}
+	return &sinkMetricsCore{Core: core, name: name, m: m, reported: reporter != nil}
+}
+
+// sinkMetricsCore 统计单个输出的写入结果
+type sinkMetricsCore struct {
+	zapcore.Core
+	name     string
+	m        *metrics
+	reported bool // 成功的条数由输出自行统计
+}
+
+func (c *sinkMetricsCore) With(fields []zapcore.Field) zapcore.Core {
+	return &sinkMetricsCore{Core: c.Core.With(fields), name: c.name, m: c.m, reported: c.reported}
+}
+
+func (c *sinkMetricsCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *sinkMetricsCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ce := c.Core.Check(ent, nil)
+	if ce == nil {
+		return nil
+	}
+
+	err := writeEntry(ce, fields)
+	if err != nil {
+		c.m.failures.inc(c.name)
+	} else if !c.reported {
+		c.m.written.inc(sinkKey{sink: c.name, level: ent.Level})
+	}
+	return err
+}
+
+// WriteMetrics 以 Prometheus 文本格式写出日志计数
+func (l *Logger) WriteMetrics(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	l.sinks.metrics.write(bw, l.sinks.dropped(), l.sinks.suppressed(), l.sinks.hooks.dropped.Load())
+	return bw.Flush()
+}
+
+// MetricsHandler 返回以 Prometheus 文本格式输出日志计数的 http.Handler，包括：
+//
+//	logger_entries_total{level,logger}       通过级别过滤的日志条数
+//	logger_sink_entries_total{sink,level}    各输出成功写入的条数
+//	logger_sink_write_failures_total{sink}   各输出写入失败的条数
+//	logger_sink_dropped_entries_total{sink}  各输出已接收但未送达而丢弃的条数，如 syslog 断线期间缓冲溢出
+//	logger_sampled_entries_total{level}      被采样丢弃的条数
+//	logger_dropped_entries_total             异步队列溢出丢弃的条数
+//	logger_suppressed_entries_total          被限流抑制的条数
+//	logger_hook_dropped_entries_total        回调队列满而未传给回调的条数
+func (l *Logger) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveMetrics(w, r, l)
+	})
+}
+
+// serveMetrics 处理指标请求
+func serveMetrics(w http.ResponseWriter, r *http.Request, l *Logger) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if l == nil {
+		http.Error(w, "logger not initialized", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = l.WriteMetrics(w)
+}
+
+// metricSample 一条指标样本
+type metricSample struct {
+	labels string
+	value  uint64
+}
+
+func (m *metrics) write(w *bufio.Writer, dropped, suppressed, hookDropped uint64) {
+	var samples []metricSample
+	m.entries.each(func(k entryKey, n uint64) {
+		samples = append(samples, metricSample{labels: formatLabels("level", k.level.String(), "logger", k.logger), value: n})
+	})
+	writeMetric(w, "logger_entries_total", "Log entries that passed level filtering, by level and logger.", samples)
+
+	samples = samples[:0]
+	m.written.each(func(k sinkKey, n uint64) {
+		samples = append(samples, metricSample{labels: formatLabels("sink", k.sink, "level", k.level.String()), value: n})
+	})
+	writeMetric(w, "logger_sink_entries_total", "Log entries successfully written, by sink and level.", samples)
+
+	samples = samples[:0]
+	m.failures.each(func(sink string, n uint64) {
+		samples = append(samples, metricSample{labels: formatLabels("sink", sink), value: n})
+	})
+	writeMetric(w, "logger_sink_write_failures_total", "Log entries that failed to be written, by sink.", samples)
+
+	samples = samples[:0]
+	m.lost.each(func(sink string, n uint64) {
+		samples = append(samples, metricSample{labels: formatLabels("sink", sink), value: n})
+	})
+	writeMetric(w, "logger_sink_dropped_entries_total", "Log entries accepted by a sink but dropped before delivery, by sink.", samples)
+
+	samples = samples[:0]
+	m.sampled.each(func(level zapcore.Level, n uint64) {
+		samples = append(samples, metricSample{labels: formatLabels("level", level.String()), value: n})
+	})
+	writeMetric(w, "logger_sampled_entries_total", "Log entries dropped by sampling, by level.", samples)
+
+	writeMetric(w, "logger_dropped_entries_total", "Log entries dropped because the async queue was full.",
+		[]metricSample{{value: dropped}})
+	writeMetric(w, "logger_suppressed_entries_total", "Log entries suppressed by rate limiting.",
+		[]metricSample{{value: suppressed}})
+	writeMetric(w, "logger_hook_dropped_entries_total", "Log entries not passed to entry hooks because a hook queue was full.",
+		[]metricSample{{value: hookDropped}})
+}
+
+// writeMetric 写出一个计数器，样本按标签排序
+func writeMetric(w *bufio.Writer, name, help string, samples []metricSample) {
+	sort.Slice(samples, func(i, j int) bool { return samples[i].labels < samples[j].labels })
+
+	w.WriteString("# HELP " + name + " " + help + "\n")
+	w.WriteString("# TYPE " + name + " counter\n")
+	for _, s := range samples {
+		w.WriteString(name)
+		w.WriteString(s.labels)
+		w.WriteByte(' ')
+		w.WriteString(strconv.FormatUint(s.value, 10))
+		w.WriteByte('\n')
+	}
+}
+
+// labelEscaper 转义标签值中的反斜杠、双引号与换行
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatLabels 将成对的标签名与值格式化为 {name="value",...}
+func formatLabels(pairs ...string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i])
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(pairs[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+)
+
+// metricsText 返回 logger 的指标文本
+func metricsText(t *testing.T, l *Logger) string {
+	t.Helper()
+	var b strings.Builder
+	if err := l.WriteMetrics(&b); err != nil {
+		t.Fatalf("WriteMetrics: %v", err)
+	}
+	return b.String()
+}
+
+// closedAddr 返回一个当前无人监听的本地地址
+func closedAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+	return addr
+}
+
+func TestMetricsSyslogDelivery(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	received := make(chan string, 16)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			if _, err := r.ReadString('}'); err != nil {
+				return
+			}
+			received <- "entry"
+		}
+	}()
+
+	l, err := New(WithOutputs(OutputConfig{
+		Name:     "sys",
+		Type:     OutputSyslog,
+		Address:  ln.Addr().String(),
+		Encoding: "json",
+		Syslog:   SyslogConfig{Network: "tcp"},
+	}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer l.Close()
+
+	l.Info("hello")
+	<-received
+
+	text := metricsText(t, l)
+	if !strings.Contains(text, `logger_sink_entries_total{sink="sys",level="info"} 1`) {
+		t.Errorf("delivered entry not counted:\n%s", text)
+	}
+}
+
+func TestMetricsSyslogDropped(t *testing.T) {
+	l, err := New(WithOutputs(OutputConfig{
+		Name:    "sys",
+		Type:    OutputSyslog,
+		Address: closedAddr(t),
+		Syslog:  SyslogConfig{Network: "tcp", BufferSize: 2},
+	}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		l.Info("unreachable")
+	}
+
+	// 缓冲中的消息尚未发出，不计为已写入
+	text := metricsText(t, l)
+	if strings.Contains(text, `logger_sink_entries_total{sink="sys"`) {
+		t.Errorf("buffered entries counted as written:\n%s", text)
+	}
+	if !strings.Contains(text, `logger_sink_dropped_entries_total{sink="sys"} 8`) {
+		t.Errorf("overflow drops not counted:\n%s", text)
+	}
+
+	// 关闭时仍未送达的消息同样计为丢弃
+	_ = l.Close()
+	text = metricsText(t, l)
+	if !strings.Contains(text, `logger_sink_dropped_entries_total{sink="sys"} 10`) {
+		t.Errorf("entries lost on close not counted:\n%s", text)
+	}
+}
@@ -0,0 +1,259 @@
+package logger
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// noOverride 表示没有任何模块级别覆盖时的最小级别
+const noOverride = math.MaxInt8
+
+// moduleRule 一条模块级别覆盖规则
+//
+//	"*"     匹配所有模块
+//	"db"    匹配 db 及其子模块 db.xxx
+//	"db.*"  只匹配 db 的子模块
+type moduleRule struct {
+	pattern string
+	level   zapcore.Level
+}
+
+func (r moduleRule) match(name string) bool {
+	switch {
+	case r.pattern == "*":
+		return true
+	case strings.HasSuffix(r.pattern, ".*"):
+		return strings.HasPrefix(name, strings.TrimSuffix(r.pattern, "*"))
+	default:
+		return name == r.pattern || strings.HasPrefix(name, r.pattern+".")
+	}
+}
+
+// moduleResult 缓存的匹配结果
+type moduleResult struct {
+	level zapcore.Level
+	ok    bool
+}
+
+// moduleLevels 模块级别覆盖表，可在运行期修改
+type moduleLevels struct {
+	mu    sync.RWMutex
+	rules []moduleRule // 按模式长度降序，越具体越靠前
+
+	min   atomic.Int32             // 所有覆盖中的最低级别
+	cache atomic.Pointer[sync.Map] // logger 名 -> moduleResult，规则变化时整体替换
+}
+
+func newModuleLevels(levels map[string]string) (*moduleLevels, error) {
+	m := &moduleLevels{}
+	m.min.Store(noOverride)
+	m.cache.Store(&sync.Map{})
+
+	for pattern, level := range levels {
+		if err := m.set(pattern, level); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// set 新增或修改一条覆盖规则
+func (m *moduleLevels) set(pattern, level string) error {
+	pattern = strings.TrimSpace(pattern)
+	if err := validateModulePattern(pattern); err != nil {
+		return err
+	}
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return fmt.Errorf("invalid level for module %s: %w", pattern, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	replaced := false
+	for i := range m.rules {
+		if m.rules[i].pattern == pattern {
+			m.rules[i].level = lvl
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		m.rules = append(m.rules, moduleRule{pattern: pattern, level: lvl})
+	}
+	m.refresh()
+	return nil
+}
+
+// replace 以 other 的规则整体替换当前规则
+func (m *moduleLevels) replace(other *moduleLevels) {
+	other.mu.RLock()
+	rules := make([]moduleRule, len(other.rules))
+	copy(rules, other.rules)
+	other.mu.RUnlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rules = rules
+	m.refresh()
+}
+
+// remove 删除一条覆盖规则
+func (m *moduleLevels) remove(pattern string) {
+	pattern = strings.TrimSpace(pattern)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.rules {
+		if m.rules[i].pattern == pattern {
+			m.rules = append(m.rules[:i], m.rules[i+1:]...)
+			m.refresh()
+			return
+		}
+	}
+}
+
+// refresh 重新排序规则、计算最低级别并清空缓存，调用方需持有写锁
+func (m *moduleLevels) refresh() {
+	sort.SliceStable(m.rules, func(i, j int) bool {
+		return len(m.rules[i].pattern) > len(m.rules[j].pattern)
+	})
+
+	lowest := int32(noOverride)
+	for _, r := range m.rules {
+		if int32(r.level) < lowest {
+			lowest = int32(r.level)
+		}
+	}
+	m.min.Store(lowest)
+	m.cache.Store(&sync.Map{})
+}
+
+// lookup 返回 logger 名对应的覆盖级别
+func (m *moduleLevels) lookup(name string) (zapcore.Level, bool) {
+	if m.min.Load() == noOverride {
+		return 0, false
+	}
+
+	cache := m.cache.Load()
+	if v, ok := cache.Load(name); ok {
+		r := v.(moduleResult)
+		return r.level, r.ok
+	}
+
+	m.mu.RLock()
+	var r moduleResult
+	for _, rule := range m.rules {
+		if rule.match(name) {
+			r = moduleResult{level: rule.level, ok: true}
+			break
+		}
+	}
+	m.mu.RUnlock()
+
+	cache.Store(name, r)
+	return r.level, r.ok
+}
+
+// snapshot 返回当前所有覆盖规则
+func (m *moduleLevels) snapshot() map[string]string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(m.rules) == 0 {
+		return nil
+	}
+	levels := make(map[string]string, len(m.rules))
+	for _, r := range m.rules {
+		levels[r.pattern] = r.level.String()
+	}
+	return levels
+}
+
+// validateModulePattern 校验模块模式："*"、"name" 或 "name.*"
+func validateModulePattern(pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("empty module pattern")
+	}
+	if pattern == "*" {
+		return nil
+	}
+	if strings.Contains(strings.TrimSuffix(pattern, ".*"), "*") {
+		return fmt.Errorf("invalid module pattern %s: '*' is only allowed as a trailing '.*'", pattern)
+	}
+	return nil
+}
+
+// levelCore 按 logger 名决定生效级别：命中模块覆盖时使用覆盖级别，否则使用全局级别
+type levelCore struct {
+	zapcore.Core
+	base    zap.AtomicLevel
+	modules *moduleLevels
+}
+
+// Enabled 只要全局级别或任一模块覆盖允许即返回 true，精确判断在 Check 中完成
+func (c *levelCore) Enabled(lvl zapcore.Level) bool {
+	lowest := c.base.Level()
+	if m := c.modules.min.Load(); m < int32(lowest) {
+		lowest = zapcore.Level(m)
+	}
+	return lvl >= lowest
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{
+		Core:    c.Core.With(fields),
+		base:    c.base,
+		modules: c.modules,
+	}
+}
+
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	level, ok := c.modules.lookup(ent.LoggerName)
+	if !ok {
+		level = c.base.Level()
+	}
+	if ent.Level < level {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// Module 返回名为 name 的子 logger，级别受 Config.ModuleLevels 控制。
+// 子 logger 与父 logger 共享输出，Close 只需在根 logger 上调用
+func (l *Logger) Module(name string) *Logger {
+	zapLogger := l.Logger.Named(name)
+	return &Logger{
+		Logger:     zapLogger,
+		sugar:      zapLogger.Sugar(),
+		level:      l.level,
+		stackLevel: l.stackLevel,
+		modules:    l.modules,
+		sinks:      l.sinks,
+	}
+}
+
+// SetModuleLevel 在运行期设置模块级别覆盖，pattern 形如 "db"、"db.*" 或 "*"
+func (l *Logger) SetModuleLevel(pattern, level string) error {
+	return l.modules.set(pattern, level)
+}
+
+// RemoveModuleLevel 删除模块级别覆盖，该模块恢复使用全局级别
+func (l *Logger) RemoveModuleLevel(pattern string) {
+	l.modules.remove(pattern)
+}
+
+// ModuleLevels 返回当前所有模块级别覆盖
+func (l *Logger) ModuleLevels() map[string]string {
+	return l.modules.snapshot()
+}
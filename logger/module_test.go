@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestModuleRuleMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"db", "db", true},
+		{"db", "db.query", true},
+		{"db", "db.query.slow", true},
+		{"db", "dbx", false},
+		{"db", "cache.db", false},
+		{"db.*", "db", false},
+		{"db.*", "db.query", true},
+		{"db.*", "db.query.slow", true},
+		{"db.*", "dbx.query", false},
+		{"*", "", true},
+		{"*", "http", true},
+		{"*", "db.query", true},
+	}
+	for _, tt := range tests {
+		r := moduleRule{pattern: tt.pattern}
+		if got := r.match(tt.name); got != tt.want {
+			t.Errorf("%q matches %q = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestModuleLevelsLookup(t *testing.T) {
+	m, err := newModuleLevels(map[string]string{
+		"*":        "error",
+		"db":       "warn",
+		"db.*":     "info",
+		"db.query": "debug",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 越具体的模式优先
+	tests := []struct {
+		name string
+		want zapcore.Level
+	}{
+		{"db.query", zapcore.DebugLevel},
+		{"db.pool", zapcore.InfoLevel},
+		{"db", zapcore.WarnLevel},
+		{"http", zapcore.ErrorLevel},
+		{"", zapcore.ErrorLevel},
+	}
+	for _, tt := range tests {
+		if got, ok := m.lookup(tt.name); !ok || got != tt.want {
+			t.Errorf("lookup(%q) = %s, %v; want %s", tt.name, got, ok, tt.want)
+		}
+	}
+}
+
+func TestModuleLevelsInvalid(t *testing.T) {
+	for _, levels := range []map[string]string{
+		{"": "debug"},
+		{"db*": "debug"},
+		{"*.db": "debug"},
+		{"db": "loud"},
+	} {
+		if _, err := newModuleLevels(levels); err == nil {
+			t.Errorf("%v: expected error", levels)
+		}
+	}
+}
+
+func TestModuleLevelCacheInvalidated(t *testing.T) {
+	l, logs := newObservedLogger(t)
+	db := l.Module("db")
+	query := db.Module("query")
+
+	// 未设置覆盖时沿用全局级别，结果已被缓存
+	query.Debug("hidden")
+	if _, ok := l.modules.lookup("db.query"); ok {
+		t.Fatal("db.query has an override before any was set")
+	}
+
+	if err := l.SetModuleLevel("db", "debug"); err != nil {
+		t.Fatal(err)
+	}
+	query.Debug("db rule")
+
+	// 更具体的规则覆盖已缓存的结果
+	if err := l.SetModuleLevel("db.*", "error"); err != nil {
+		t.Fatal(err)
+	}
+	query.Warn("hidden")
+	db.Debug("db itself")
+
+	// 修改已有规则
+	if err := l.SetModuleLevel("db.*", "warn"); err != nil {
+		t.Fatal(err)
+	}
+	query.Warn("modified rule")
+
+	// 删除后恢复全局级别
+	l.RemoveModuleLevel("db.*")
+	l.RemoveModuleLevel("db")
+	query.Debug("hidden")
+	query.Info("removed")
+
+	got := messages(logs)
+	want := []string{"db rule", "db itself", "modified rule", "removed"}
+	if len(got) != len(want) {
+		t.Fatalf("messages = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("messages = %q, want %q", got, want)
+		}
+	}
+	if levels := l.ModuleLevels(); levels != nil {
+		t.Errorf("ModuleLevels = %v, want none", levels)
+	}
+}
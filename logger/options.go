@@ -1,8 +1,11 @@
 package logger
 
 import (
+	"errors"
 	"os"
 	"strings"
+
+	"go.uber.org/zap/zapcore"
 )
 
 // Option 配置选项
@@ -113,6 +116,33 @@ func WithCallerSkip(skip int) Option {
 	}
 }
 
+// WithStackFormat 设置堆栈格式，structured 格式下丢弃 filter 中的包并保留开头 head 帧与末尾 tail 帧
+func WithStackFormat(format string, head, tail int, filter ...string) Option {
+	return func(c *Config) {
+		c.StackFormat = format
+		c.StackHeadFrames = head
+		c.StackTailFrames = tail
+		if len(filter) > 0 {
+			c.StackFilter = filter
+		}
+	}
+}
+
+// WithCallerFormat 设置调用位置格式，prefixes 为 trim 格式去掉的包路径前缀
+func WithCallerFormat(format string, prefixes ...string) Option {
+	return func(c *Config) {
+		c.CallerFormat = format
+		c.CallerTrimPrefixes = prefixes
+	}
+}
+
+// WithCallerFunction 设置是否输出调用位置的函数名
+func WithCallerFunction(enabled bool) Option {
+	return func(c *Config) {
+		c.CallerFunction = enabled
+	}
+}
+
 // WithSampling 配置采样
 func WithSampling(enabled bool, initial, after int) Option {
 	return func(c *Config) {
@@ -126,50 +156,232 @@ func WithSampling(enabled bool, initial, after int) Option {
 	}
 }
 
-// WithConfig 使用完整配置
+// WithConfig 使用完整配置。
+// 会覆盖之前选项的设置（WithCore 追加的输出与之前选项的错误除外），应放在其他选项之前
 func WithConfig(cfg *Config) Option {
 	return func(c *Config) {
-		*c = *cfg
+		replaceConfig(c, cfg)
 	}
 }
 
-// WithEnvAutoConfig 根据环境变量自动配置
+// replaceConfig 以 cfg 取代 c 的配置，保留之前选项追加的输出、产生的错误与记录的字段来源
+func replaceConfig(c, cfg *Config) {
+	next := *cfg
+	next.cores = append(c.cores[:len(c.cores):len(c.cores)], cfg.cores...)
+	next.optErr = c.optErr
+	next.addErr(cfg.optErr)
+
+	next.sources = make(map[string]valueSource, len(c.sources)+len(cfg.sources))
+	for k, v := range c.sources {
+		next.sources[k] = v
+	}
+	for k, v := range cfg.sources {
+		next.sources[k] = v
+	}
+	*c = next
+}
+
+// legacyEnvVars WithEnvAutoConfig 兼容的旧变量名，同一字段按顺序取第一个存在的变量
+var legacyEnvVars = []struct {
+	name string
+	path string
+}{
+	{"LOG_ENV", "environment"},
+	{"ENV", "environment"},
+	{"LOG_DIR", "log_dir"},
+	{"LOG_FILE_ENABLED", "enable_file"},
+	{"LOG_CONSOLE_ENABLED", "enable_console"},
+}
+
+// WithEnvAutoConfig 根据环境变量自动配置：绑定全部 LOG_ 前缀变量（见 WithEnv），
+// 并兼容 LOG_ENV/ENV、LOG_DIR、LOG_FILE_ENABLED、LOG_CONSOLE_ENABLED
 func WithEnvAutoConfig() Option {
 	return func(c *Config) {
-		// LOG_LEVEL
-		if level := os.Getenv("LOG_LEVEL"); level != "" {
-			c.Level = level
-		}
+		var errs []error
 
-		// LOG_ENV or ENV
-		if env := os.Getenv("LOG_ENV"); env != "" {
-			c.Environment = env
-		} else if env := os.Getenv("ENV"); env != "" {
-			c.Environment = env
+		// 旧变量名优先级低于完整变量名
+		seen := make(map[string]bool)
+		for _, v := range legacyEnvVars {
+			raw, ok := os.LookupEnv(v.name)
+			if !ok || strings.TrimSpace(raw) == "" || seen[v.path] {
+				continue
+			}
+			seen[v.path] = true
+			f, _ := lookupConfigField(v.path)
+			if err := c.setFromEnv(f, v.name, raw); err != nil {
+				errs = append(errs, err)
+			}
 		}
+		errs = append(errs, bindEnv(c, DefaultEnvPrefix, os.LookupEnv))
+		c.addErr(errors.Join(errs...))
 
-		// 根据环境自动调整
-		if strings.EqualFold(c.Environment, "production") ||
-			strings.EqualFold(c.Environment, "prod") {
+		// prod、PRODUCTION 等写法统一为 production
+		if c.Environment != "production" && (strings.EqualFold(c.Environment, "production") ||
+			strings.EqualFold(c.Environment, "prod")) {
 			c.Environment = "production"
-			c.Encoding = "json"
-			c.EnableSampling = true
-			c.ColorConsole = false
+			if s, ok := c.sources["environment"]; ok {
+				c.recordSourceByPath("environment", s.from)
+			}
 		}
+	}
+}
 
-		// LOG_DIR
-		if dir := os.Getenv("LOG_DIR"); dir != "" {
-			c.LogDir = dir
+// WithFilePattern 设置文件名中间部分的模式，如 "%Y%m%d%H.%N"
+func WithFilePattern(pattern string) Option {
+	return func(c *Config) {
+		c.FilePattern = pattern
+	}
+}
+
+// WithMaxTotalSize 设置日志总大小上限(MB)
+func WithMaxTotalSize(size int64) Option {
+	return func(c *Config) {
+		c.MaxTotalSize = size
+	}
+}
+
+// WithModuleLevel 设置模块级别覆盖，pattern 形如 "db"、"db.*" 或 "*"
+func WithModuleLevel(pattern, level string) Option {
+	return func(c *Config) {
+		levels := make(map[string]string, len(c.ModuleLevels)+1)
+		for k, v := range c.ModuleLevels {
+			levels[k] = v
 		}
+		levels[pattern] = level
+		c.ModuleLevels = levels
+	}
+}
 
-		// LOG_FILE_ENABLED
-		if enabled := os.Getenv("LOG_FILE_ENABLED"); enabled != "" {
-			c.EnableFile = strings.EqualFold(enabled, "true")
+// WithOutputs 使用多输出配置，取代 EnableFile/EnableConsole 等平铺配置
+func WithOutputs(outputs ...OutputConfig) Option {
+	return func(c *Config) {
+		c.Outputs = outputs
+	}
+}
+
+// WithOutput 追加一个输出
+func WithOutput(output OutputConfig) Option {
+	return func(c *Config) {
+		c.Outputs = append(c.Outputs[:len(c.Outputs):len(c.Outputs)], output)
+	}
+}
+
+// WithErrorFile 配置错误日志文件，levels 为空时收录 warn 及以上级别
+func WithErrorFile(enabled bool, levels ...string) Option {
+	return func(c *Config) {
+		c.EnableErrorFile = enabled
+		if len(levels) > 0 {
+			c.ErrorFileLevels = levels
 		}
+	}
+}
 
-		// LOG_CONSOLE_ENABLED
-		if enabled := os.Getenv("LOG_CONSOLE_ENABLED"); enabled != "" {
-			c.EnableConsole = strings.EqualFold(enabled, "true")
+// WithErrorFileRetention 配置错误日志文件的保留策略
+func WithErrorFileRetention(maxAge int, rotationCount uint) Option {
+	return func(c *Config) {
+		if maxAge > 0 {
+			c.ErrorMaxAge = maxAge
+		}
+		if rotationCount > 0 {
+			c.ErrorRotationCount = rotationCount
+		}
+	}
+}
+
+// WithAsync 配置异步写入，overflow 为队列满时的策略，dropLevel 仅在 drop_below 策略下使用
+func WithAsync(enabled bool, queueSize int, overflow, dropLevel string) Option {
+	return func(c *Config) {
+		c.EnableAsync = enabled
+		if queueSize > 0 {
+			c.AsyncQueueSize = queueSize
+		}
+		if overflow != "" {
+			c.AsyncOverflow = overflow
+		}
+		if dropLevel != "" {
+			c.AsyncDropLevel = dropLevel
+		}
+	}
+}
+
+// WithRateLimit 设置限流配置
+func WithRateLimit(rateLimit RateLimitConfig) Option {
+	return func(c *Config) {
+		c.RateLimit = rateLimit
+	}
+}
+
+// WithRedaction 设置脱敏配置
+func WithRedaction(redaction RedactionConfig) Option {
+	return func(c *Config) {
+		c.Redaction = redaction
+	}
+}
+
+// WithRedactKeys 追加需脱敏的字段名，支持 glob，如 "*token*"
+func WithRedactKeys(keys ...string) Option {
+	return func(c *Config) {
+		c.Redaction.Keys = append(c.Redaction.Keys[:len(c.Redaction.Keys):len(c.Redaction.Keys)], keys...)
+	}
+}
+
+// WithRedactPatterns 追加对消息与字符串值做替换的正则
+func WithRedactPatterns(patterns ...string) Option {
+	return func(c *Config) {
+		c.Redaction.Patterns = append(c.Redaction.Patterns[:len(c.Redaction.Patterns):len(c.Redaction.Patterns)], patterns...)
+	}
+}
+
+// WithConfigFile 从 YAML/JSON 文件读取配置，文件中未出现的字段使用默认值。
+// 会覆盖之前选项的设置（WithCore 追加的输出与之前选项的错误除外），应放在其他选项之前；读取失败时由 New 返回错误
+func WithConfigFile(path string) Option {
+	return func(c *Config) {
+		cfg, err := LoadConfig(path)
+		if err != nil {
+			c.addErr(err)
+			return
+		}
+
+		replaceConfig(c, cfg)
+	}
+}
+
+// WithCore 追加自定义输出 core（如测试中的 observer），与其他输出并列，不受输出级别区间影响
+func WithCore(core zapcore.Core) Option {
+	return func(c *Config) {
+		c.cores = append(c.cores[:len(c.cores):len(c.cores)], core)
+	}
+}
+
+// WithSlogDefault 设置 SetGlobal 时是否同时调用 slog.SetDefault
+func WithSlogDefault(enabled bool) Option {
+	return func(c *Config) {
+		c.SlogDefault = enabled
+	}
+}
+
+// WithCrashReport 设置 Go 与 Recover 捕获 panic 时的处理：是否写入崩溃报告、报告目录（为空时为 LogDir）及是否重新 panic
+func WithCrashReport(enabled bool, dir string, repanic bool) Option {
+	return func(c *Config) {
+		c.CrashReport = enabled
+		c.CrashDir = dir
+		c.CrashRepanic = repanic
+	}
+}
+
+// WithCrashMaxReports 设置保留的崩溃报告数量，0 表示不限制
+func WithCrashMaxReports(n int) Option {
+	return func(c *Config) {
+		c.CrashMaxReports = n
+	}
+}
+
+// WithStdLog 设置 Init 时是否将标准库 log 的输出重定向到本日志，level 为空时为 info
+func WithStdLog(redirect bool, level string) Option {
+	return func(c *Config) {
+		c.RedirectStdLog = redirect
+		if level != "" {
+			c.StdLogLevel = level
 		}
 	}
 }
@@ -0,0 +1,66 @@
+package logger_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/constellation39/framework/logger"
+	"github.com/constellation39/framework/logger/loggertest"
+	"go.uber.org/zap/zapcore"
+)
+
+// writeConfigFile 在临时目录写入配置文件并返回路径
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// replacingOptions 返回以配置文件内容取代整个配置的两种选项
+func replacingOptions(t *testing.T, content string) map[string]logger.Option {
+	t.Helper()
+	path := writeConfigFile(t, "log.yaml", content)
+	cfg, err := logger.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	return map[string]logger.Option{
+		"WithConfigFile": logger.WithConfigFile(path),
+		"WithConfig":     logger.WithConfig(cfg),
+	}
+}
+
+func TestReplacingConfigKeepsCores(t *testing.T) {
+	for name, opt := range replacingOptions(t, "level: warn\nenable_file: false\nenable_console: false\n") {
+		t.Run(name, func(t *testing.T) {
+			r := loggertest.New(t, opt)
+			r.Info("hidden")
+			r.Warn("shown")
+
+			r.RequireNotLogged(t, zapcore.InfoLevel, "hidden")
+			r.RequireLogged(t, zapcore.WarnLevel, "shown")
+		})
+	}
+}
+
+func TestReplacingConfigKeepsEarlierErrors(t *testing.T) {
+	t.Setenv("LOG_ROTATION_SIZE", "bogus")
+
+	for name, opt := range replacingOptions(t, "enable_file: false\nenable_console: true\n") {
+		t.Run(name, func(t *testing.T) {
+			l, err := logger.New(logger.WithEnv(""), opt)
+			if err == nil {
+				_ = l.Close()
+				t.Fatal("expected error from LOG_ROTATION_SIZE")
+			}
+			if !strings.Contains(err.Error(), "LOG_ROTATION_SIZE") {
+				t.Errorf("error = %v, want it to mention LOG_ROTATION_SIZE", err)
+			}
+		})
+	}
+}
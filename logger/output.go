@@ -0,0 +1,665 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/constellation39/framework/logger/rotate"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 输出类型
+const (
+	OutputFile   = "file"
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+	OutputTCP    = "tcp"
+	OutputUDP    = "udp"
+	OutputUnix   = "unix"
+	OutputSyslog = "syslog"
+)
+
+// OutputConfig 单个输出目标配置
+type OutputConfig struct {
+	Name     string        `json:"name" yaml:"name"`           // 输出名称，默认为输出类型
+	Type     string        `json:"type" yaml:"type"`           // 输出类型: file, stdout, stderr, tcp, udp, unix, syslog
+	MinLevel string        `json:"min_level" yaml:"min_level"` // 最低级别，为空时只受全局级别限制
+	MaxLevel string        `json:"max_level" yaml:"max_level"` // 最高级别，为空时不限制
+	Encoding string        `json:"encoding" yaml:"encoding"`   // 编码格式: json, console；为空时文件与网络为 json，控制台沿用 Config.Encoding
+	Encoder  EncoderConfig `json:"encoder" yaml:"encoder"`     // 编码器配置
+
+	// 文件输出配置，仅 Type 为 file 时有效
+	File FileOutputConfig `json:"file" yaml:"file"`
+
+	// 网络输出地址，仅 Type 为 tcp、udp、unix、syslog 时有效，如 "127.0.0.1:5170"、"/var/run/log.sock"
+	Address string `json:"address" yaml:"address"`
+
+	// syslog 输出配置，仅 Type 为 syslog 时有效
+	Syslog SyslogConfig `json:"syslog" yaml:"syslog"`
+}
+
+// EncoderConfig 编码器配置，字段为空时使用默认值
+type EncoderConfig struct {
+	TimeKey        string `json:"time_key" yaml:"time_key"`               // 默认 time
+	LevelKey       string `json:"level_key" yaml:"level_key"`             // 默认 level
+	NameKey        string `json:"name_key" yaml:"name_key"`               // 默认 logger
+	CallerKey      string `json:"caller_key" yaml:"caller_key"`           // 默认 caller
+	MessageKey     string `json:"message_key" yaml:"message_key"`         // 默认 msg
+	StacktraceKey  string `json:"stacktrace_key" yaml:"stacktrace_key"`   // 默认 stacktrace
+	TimeFormat     string `json:"time_format" yaml:"time_format"`         // iso8601, rfc3339, rfc3339nano, epoch, epoch_millis, epoch_nanos 或 Go 时间布局
+	LevelFormat    string `json:"level_format" yaml:"level_format"`       // capital, capital_color, lowercase, lowercase_color
+	DurationFormat string `json:"duration_format" yaml:"duration_format"` // millis, seconds, nanos, string
+	CallerFormat   string `json:"caller_format" yaml:"caller_format"`     // short, full, module, trim；为空时沿用 Config.CallerFormat
+	FunctionKey    string `json:"function_key" yaml:"function_key"`       // 默认 func，仅 Config.CallerFunction 启用时输出
+	StackFormat    string `json:"stack_format" yaml:"stack_format"`       // text, structured；为空时沿用 Config.StackFormat
+}
+
+// FileOutputConfig 文件输出配置，字段为零值时沿用 Config 中的同名配置
+type FileOutputConfig struct {
+	Dir           string `json:"dir" yaml:"dir"`                       // 日志目录
+	Filename      string `json:"filename" yaml:"filename"`             // 日志文件名前缀
+	Pattern       string `json:"pattern" yaml:"pattern"`               // 文件名中间部分的模式
+	MaxAge        int    `json:"max_age" yaml:"max_age"`               // 日志保留天数
+	RotationTime  int    `json:"rotation_time" yaml:"rotation_time"`   // 轮转时间(小时)
+	RotationSize  int64  `json:"rotation_size" yaml:"rotation_size"`   // 轮转大小(MB)
+	RotationCount uint   `json:"rotation_count" yaml:"rotation_count"` // 保留文件数量
+	MaxTotalSize  int64  `json:"max_total_size" yaml:"max_total_size"` // 日志总大小上限(MB)
+	Compress      bool   `json:"compress" yaml:"compress"`             // 是否压缩旧日志
+
+	// 按级别分流的附加文件
+	Routes []FileRouteConfig `json:"routes" yaml:"routes"`
+}
+
+// FileRouteConfig 按级别分流的附加文件，命中的日志仍会写入主文件，级别同样受所属输出的区间限制。
+// File 中未设置的字段沿用所属文件输出的配置，Filename 默认为主文件名加 ".error"
+type FileRouteConfig struct {
+	Levels []string         `json:"levels" yaml:"levels"` // 写入该文件的级别，如 ["warn", "error", "dpanic", "panic", "fatal"]
+	File   FileOutputConfig `json:"file" yaml:"file"`     // 文件与保留策略
+}
+
+// defaultErrorLevels 错误日志文件默认收录的级别
+var defaultErrorLevels = []string{"warn", "error", "dpanic", "panic", "fatal"}
+
+// resolvedOutputs 返回实际生效的输出列表。
+// 未配置 Outputs 时，由 EnableFile/EnableConsole 等平铺字段映射得到
+func (c *Config) resolvedOutputs() []OutputConfig {
+	if len(c.Outputs) > 0 {
+		return c.Outputs
+	}
+
+	outputs := make([]OutputConfig, 0, 2)
+	if c.EnableFile {
+		out := OutputConfig{
+			Name:     OutputFile,
+			Type:     OutputFile,
+			Encoding: "json",
+		}
+		if c.EnableErrorFile {
+			levels := c.ErrorFileLevels
+			if len(levels) == 0 {
+				levels = defaultErrorLevels
+			}
+			out.File.Routes = []FileRouteConfig{{
+				Levels: levels,
+				File: FileOutputConfig{
+					Filename:      c.Filename + ".error",
+					MaxAge:        c.ErrorMaxAge,
+					RotationCount: c.ErrorRotationCount,
+				},
+			}}
+		}
+		outputs = append(outputs, out)
+	}
+	if c.EnableConsole {
+		outputs = append(outputs, OutputConfig{
+			Name:     "console",
+			Type:     OutputStdout,
+			Encoding: c.Encoding,
+		})
+	}
+	return outputs
+}
+
+// buildOutputs 构建所有输出 core，失败时关闭已打开的资源
+func buildOutputs(cfg *Config, m *metrics) ([]zapcore.Core, []io.Closer, error) {
+	outputs := cfg.resolvedOutputs()
+	cores := make([]zapcore.Core, 0, len(outputs))
+	closers := make([]io.Closer, 0, len(outputs))
+
+	for i := range outputs {
+		core, closer, err := buildOutputCore(cfg, &outputs[i])
+		if err != nil {
+			for _, c := range closers {
+				_ = c.Close()
+			}
+			return nil, nil, fmt.Errorf("failed to build output %s: %w", outputName(&outputs[i], i), err)
+		}
+		reporter, _ := closer.(sinkReporter)
+		cores = append(cores, m.sink(outputName(&outputs[i], i), core, reporter))
+		if closer != nil {
+			closers = append(closers, closer)
+		}
+	}
+	return cores, closers, nil
+}
+
+// outputName 返回输出名称，未配置时使用类型与序号
+func outputName(out *OutputConfig, index int) string {
+	if out.Name != "" {
+		return out.Name
+	}
+	return fmt.Sprintf("%s[%d]", out.Type, index)
+}
+
+// buildOutputCore 构建单个输出 core
+func buildOutputCore(cfg *Config, out *OutputConfig) (zapcore.Core, io.Closer, error) {
+	enabler, err := newLevelRange(out.MinLevel, out.MaxLevel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		ws     zapcore.WriteSyncer
+		closer io.Closer
+	)
+
+	switch strings.ToLower(out.Type) {
+	case OutputFile:
+		return buildFileOutputCore(cfg, out, enabler)
+	case OutputSyslog:
+		core, w, err := buildSyslogCore(cfg, out, enabler)
+		if err != nil {
+			return nil, nil, err
+		}
+		return wrapOutputCore(cfg, out, core), w, nil
+	case OutputStdout:
+		ws = zapcore.Lock(os.Stdout)
+	case OutputStderr:
+		ws = zapcore.Lock(os.Stderr)
+	case OutputTCP, OutputUDP, OutputUnix:
+		if out.Address == "" {
+			return nil, nil, fmt.Errorf("address is required for %s output", out.Type)
+		}
+		w := newNetWriter(strings.ToLower(out.Type), out.Address)
+		ws, closer = zapcore.AddSync(w), w
+	default:
+		return nil, nil, fmt.Errorf("unknown output type: %q", out.Type)
+	}
+
+	encoder, err := buildEncoder(cfg, out)
+	if err != nil {
+		if closer != nil {
+			_ = closer.Close()
+		}
+		return nil, nil, err
+	}
+
+	return wrapOutputCore(cfg, out, zapcore.NewCore(encoder, ws, enabler)), closer, nil
+}
+
+// buildFileOutputCore 构建文件输出 core，包括按级别分流的附加文件
+func buildFileOutputCore(cfg *Config, out *OutputConfig, enabler zapcore.LevelEnabler) (zapcore.Core, io.Closer, error) {
+	var closers closerList
+	fail := func(err error) (zapcore.Core, io.Closer, error) {
+		_ = closers.Close()
+		return nil, nil, err
+	}
+
+	// 主文件
+	w, err := newRotateWriter(cfg, &out.File)
+	if err != nil {
+		return fail(err)
+	}
+	closers = append(closers, w)
+
+	encoder, err := buildEncoder(cfg, out)
+	if err != nil {
+		return fail(err)
+	}
+	cores := []zapcore.Core{wrapOutputCore(cfg, out, zapcore.NewCore(encoder, w, enabler))}
+
+	// 分流文件
+	for i := range out.File.Routes {
+		route := &out.File.Routes[i]
+
+		levels, err := newLevelSet(route.Levels)
+		if err != nil {
+			return fail(fmt.Errorf("route %d: %w", i, err))
+		}
+
+		fc := inheritFileConfig(route.File, out.File)
+		if route.File.Filename == "" {
+			fc.Filename = firstNonEmpty(out.File.Filename, cfg.Filename) + ".error"
+		}
+		rw, err := newRotateWriter(cfg, &fc)
+		if err != nil {
+			return fail(fmt.Errorf("route %d: %w", i, err))
+		}
+		closers = append(closers, rw)
+
+		routeEncoder, err := buildEncoder(cfg, out)
+		if err != nil {
+			return fail(err)
+		}
+		// 分流文件属于该输出，同样受输出级别区间限制
+		cores = append(cores, wrapOutputCore(cfg, out, zapcore.NewCore(routeEncoder, rw, levels.within(enabler))))
+	}
+
+	return zapcore.NewTee(cores...), closers, nil
+}
+
+// inheritFileConfig 以 parent 补全 child 中未设置的字段
+func inheritFileConfig(child, parent FileOutputConfig) FileOutputConfig {
+	fc := child
+	fc.Dir = firstNonEmpty(fc.Dir, parent.Dir)
+	fc.Pattern = firstNonEmpty(fc.Pattern, parent.Pattern)
+	if fc.MaxAge == 0 {
+		fc.MaxAge = parent.MaxAge
+	}
+	if fc.RotationTime == 0 {
+		fc.RotationTime = parent.RotationTime
+	}
+	if fc.RotationSize == 0 {
+		fc.RotationSize = parent.RotationSize
+	}
+	if fc.RotationCount == 0 {
+		fc.RotationCount = parent.RotationCount
+	}
+	if fc.MaxTotalSize == 0 {
+		fc.MaxTotalSize = parent.MaxTotalSize
+	}
+	fc.Compress = fc.Compress || parent.Compress
+	fc.Routes = nil
+	return fc
+}
+
+// wrapOutputCore 为单个输出 core 添加通用包装。
+// 需包在叶子 core 上，以免包装层的 Check 越过各输出自身的级别判断
+func wrapOutputCore(cfg *Config, out *OutputConfig, core zapcore.Core) zapcore.Core {
+	if !cfg.EnableStacktrace {
+		return core
+	}
+
+	// 结构化堆栈
+	if strings.ToLower(firstNonEmpty(out.Encoder.StackFormat, cfg.StackFormat)) == StackStructured {
+		return newStructuredStackCore(cfg, out, core)
+	}
+
+	// 包装堆栈截断
+	if cfg.MaxStackFrames > 0 {
+		core = &stackTrimCore{
+			Core:      core,
+			maxFrames: cfg.MaxStackFrames,
+		}
+	}
+	return core
+}
+
+// outputEncoding 返回输出的编码格式，未设置时文件与网络为 json，控制台沿用 Config.Encoding
+func outputEncoding(cfg *Config, out *OutputConfig) string {
+	if out.Encoding != "" {
+		return out.Encoding
+	}
+	if isConsoleOutput(out) {
+		return cfg.Encoding
+	}
+	return "json"
+}
+
+// isConsoleOutput 判断是否为控制台输出
+func isConsoleOutput(out *OutputConfig) bool {
+	t := strings.ToLower(out.Type)
+	return t == OutputStdout || t == OutputStderr
+}
+
+// newRotateWriter 按配置创建轮转文件写入器，未设置的字段沿用 Config 中的同名配置
+func newRotateWriter(cfg *Config, fc *FileOutputConfig) (*rotate.Writer, error) {
+	dir := firstNonEmpty(fc.Dir, cfg.LogDir)
+	filename := firstNonEmpty(fc.Filename, cfg.Filename)
+	filePattern := firstNonEmpty(fc.Pattern, cfg.FilePattern, "%Y%m%d.%N")
+
+	rotationTime := fc.RotationTime
+	if rotationTime == 0 {
+		rotationTime = cfg.RotationTime
+	}
+	rotationSize := fc.RotationSize
+	if rotationSize == 0 {
+		rotationSize = cfg.RotationSize
+	}
+	rotationCount := fc.RotationCount
+	if rotationCount == 0 {
+		rotationCount = cfg.RotationCount
+	}
+	maxAge := fc.MaxAge
+	if maxAge == 0 {
+		maxAge = cfg.MaxAge
+	}
+	maxTotalSize := fc.MaxTotalSize
+	if maxTotalSize == 0 {
+		maxTotalSize = cfg.MaxTotalSize
+	}
+
+	// 创建日志目录
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	// 构建日志文件路径
+	logPath := filepath.Join(dir, filename+"."+filePattern+".log")
+	linkPath := filepath.Join(dir, filename+".log")
+
+	rotateOpts := []rotate.Option{
+		rotate.WithLinkName(linkPath),
+		rotate.WithRotationTime(time.Duration(rotationTime) * time.Hour),
+		rotate.WithRotationSize(rotationSize * 1024 * 1024),
+		rotate.WithMaxFiles(rotationCount),
+		rotate.WithMaxAge(time.Duration(maxAge) * 24 * time.Hour),
+		rotate.WithMaxTotalSize(maxTotalSize * 1024 * 1024),
+		rotate.WithCompress(fc.Compress || cfg.CompressOldLog),
+	}
+
+	logWriter, err := rotate.New(logPath, rotateOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rotate writer: %w", err)
+	}
+	return logWriter, nil
+}
+
+// buildEncoder 构建编码器
+func buildEncoder(cfg *Config, out *OutputConfig) (zapcore.Encoder, error) {
+	var encoderConfig zapcore.EncoderConfig
+
+	if cfg.Environment == "production" {
+		encoderConfig = zap.NewProductionEncoderConfig()
+	} else {
+		encoderConfig = zap.NewDevelopmentEncoderConfig()
+	}
+
+	ec := &out.Encoder
+	isConsole := isConsoleOutput(out)
+
+	// 统一字段名
+	encoderConfig.TimeKey = firstNonEmpty(ec.TimeKey, "time")
+	encoderConfig.LevelKey = firstNonEmpty(ec.LevelKey, "level")
+	encoderConfig.NameKey = firstNonEmpty(ec.NameKey, "logger")
+	encoderConfig.CallerKey = firstNonEmpty(ec.CallerKey, "caller")
+	encoderConfig.FunctionKey = zapcore.OmitKey
+	if cfg.CallerFunction {
+		encoderConfig.FunctionKey = firstNonEmpty(ec.FunctionKey, "func")
+	}
+	encoderConfig.MessageKey = firstNonEmpty(ec.MessageKey, "msg")
+	encoderConfig.StacktraceKey = firstNonEmpty(ec.StacktraceKey, "stacktrace")
+	encoderConfig.LineEnding = zapcore.DefaultLineEnding
+
+	timeEncoder, err := parseTimeEncoder(ec.TimeFormat)
+	if err != nil {
+		return nil, err
+	}
+	encoderConfig.EncodeTime = timeEncoder
+
+	durationEncoder, err := parseDurationEncoder(ec.DurationFormat)
+	if err != nil {
+		return nil, err
+	}
+	encoderConfig.EncodeDuration = durationEncoder
+
+	// 调用位置
+	callerFormat := firstNonEmpty(ec.CallerFormat, cfg.CallerFormat)
+	if callerFormat == "" {
+		callerFormat = CallerShort
+		if isConsole {
+			callerFormat = CallerModule
+		}
+	}
+	callerEncoder, err := newCallerEncoder(callerFormat, cfg.CallerTrimPrefixes)
+	if err != nil {
+		return nil, err
+	}
+	encoderConfig.EncodeCaller = callerEncoder
+
+	// 控制台特殊配置
+	levelFormat := ec.LevelFormat
+	if isConsole {
+		if levelFormat == "" {
+			levelFormat = "capital"
+			if cfg.ColorConsole {
+				levelFormat = "capital_color"
+			}
+		}
+	} else {
+		if levelFormat == "" {
+			levelFormat = "lowercase"
+		}
+	}
+
+	levelEncoder, err := parseLevelEncoder(levelFormat)
+	if err != nil {
+		return nil, err
+	}
+	encoderConfig.EncodeLevel = levelEncoder
+
+	// 根据编码格式创建编码器
+	encoding := outputEncoding(cfg, out)
+	switch strings.ToLower(encoding) {
+	case "json":
+		return zapcore.NewJSONEncoder(encoderConfig), nil
+	case "console":
+		return zapcore.NewConsoleEncoder(encoderConfig), nil
+	default:
+		return nil, fmt.Errorf("unknown encoding: %q", encoding)
+	}
+}
+
+// parseTimeEncoder 解析时间格式
+func parseTimeEncoder(format string) (zapcore.TimeEncoder, error) {
+	switch strings.ToLower(format) {
+	case "", "iso8601":
+		return zapcore.ISO8601TimeEncoder, nil
+	case "rfc3339":
+		return zapcore.RFC3339TimeEncoder, nil
+	case "rfc3339nano":
+		return zapcore.RFC3339NanoTimeEncoder, nil
+	case "epoch":
+		return zapcore.EpochTimeEncoder, nil
+	case "epoch_millis":
+		return zapcore.EpochMillisTimeEncoder, nil
+	case "epoch_nanos":
+		return zapcore.EpochNanosTimeEncoder, nil
+	default:
+		// 其余视为 Go 时间布局
+		if !strings.ContainsAny(format, "0123456789") {
+			return nil, fmt.Errorf("unknown time format: %q", format)
+		}
+		return zapcore.TimeEncoderOfLayout(format), nil
+	}
+}
+
+// parseLevelEncoder 解析级别格式
+func parseLevelEncoder(format string) (zapcore.LevelEncoder, error) {
+	switch strings.ToLower(format) {
+	case "capital":
+		return zapcore.CapitalLevelEncoder, nil
+	case "capital_color":
+		return zapcore.CapitalColorLevelEncoder, nil
+	case "lowercase":
+		return zapcore.LowercaseLevelEncoder, nil
+	case "lowercase_color":
+		return zapcore.LowercaseColorLevelEncoder, nil
+	default:
+		return nil, fmt.Errorf("unknown level format: %q", format)
+	}
+}
+
+// parseDurationEncoder 解析时长格式
+func parseDurationEncoder(format string) (zapcore.DurationEncoder, error) {
+	switch strings.ToLower(format) {
+	case "", "millis":
+		return zapcore.MillisDurationEncoder, nil
+	case "seconds":
+		return zapcore.SecondsDurationEncoder, nil
+	case "nanos":
+		return zapcore.NanosDurationEncoder, nil
+	case "string":
+		return zapcore.StringDurationEncoder, nil
+	default:
+		return nil, fmt.Errorf("unknown duration format: %q", format)
+	}
+}
+
+// levelRange 级别区间 [min, max]
+type levelRange struct {
+	min zapcore.Level
+	max zapcore.Level
+}
+
+func newLevelRange(minLevel, maxLevel string) (levelRange, error) {
+	r := levelRange{min: zapcore.DebugLevel, max: zapcore.FatalLevel}
+	if minLevel != "" {
+		lvl, err := parseLevel(minLevel)
+		if err != nil {
+			return r, fmt.Errorf("invalid min level %s: %w", minLevel, err)
+		}
+		r.min = lvl
+	}
+	if maxLevel != "" {
+		lvl, err := parseLevel(maxLevel)
+		if err != nil {
+			return r, fmt.Errorf("invalid max level %s: %w", maxLevel, err)
+		}
+		r.max = lvl
+	}
+	if r.min > r.max {
+		return r, fmt.Errorf("min level %s is above max level %s", r.min, r.max)
+	}
+	return r, nil
+}
+
+// Enabled 实现 zapcore.LevelEnabler
+func (r levelRange) Enabled(lvl zapcore.Level) bool {
+	return lvl >= r.min && lvl <= r.max
+}
+
+// levelSet 级别集合
+type levelSet uint16
+
+func newLevelSet(levels []string) (levelSet, error) {
+	if len(levels) == 0 {
+		return 0, fmt.Errorf("levels must not be empty")
+	}
+	var set levelSet
+	for _, level := range levels {
+		lvl, err := parseLevel(level)
+		if err != nil {
+			return 0, err
+		}
+		set |= 1 << uint(lvl-zapcore.DebugLevel)
+	}
+	return set, nil
+}
+
+// within 去掉 enabler 不允许的级别
+func (s levelSet) within(enabler zapcore.LevelEnabler) levelSet {
+	for lvl := zapcore.DebugLevel; lvl <= zapcore.FatalLevel; lvl++ {
+		if !enabler.Enabled(lvl) {
+			s &^= 1 << uint(lvl-zapcore.DebugLevel)
+		}
+	}
+	return s
+}
+
+// Enabled 实现 zapcore.LevelEnabler
+func (s levelSet) Enabled(lvl zapcore.Level) bool {
+	if lvl < zapcore.DebugLevel || lvl > zapcore.FatalLevel {
+		return false
+	}
+	return s&(1<<uint(lvl-zapcore.DebugLevel)) != 0
+}
+
+// netWriter 网络输出，连接断开后在下次写入时自动重连
+type netWriter struct {
+	network string
+	address string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+const netDialTimeout = 3 * time.Second
+
+func newNetWriter(network, address string) *netWriter {
+	return &netWriter{network: network, address: address}
+}
+
+// Write 每次调用写入一条完整日志，失败时重连并重试一次
+func (w *netWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for attempt := 0; ; attempt++ {
+		if w.conn == nil {
+			conn, err := net.DialTimeout(w.network, w.address, netDialTimeout)
+			if err != nil {
+				return 0, fmt.Errorf("failed to dial %s %s: %w", w.network, w.address, err)
+			}
+			w.conn = conn
+		}
+
+		n, err := w.conn.Write(p)
+		if err == nil || attempt > 0 {
+			return n, err
+		}
+
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+}
+
+// Close 关闭连接
+func (w *netWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// closerList 一组需要一起关闭的资源
+type closerList []io.Closer
+
+// Close 依次关闭所有资源
+func (l closerList) Close() error {
+	return closeAll(l)
+}
+
+// closeAll 依次关闭所有资源并合并错误
+func closeAll(closers []io.Closer) error {
+	var errs []error
+	for _, c := range closers {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// firstNonEmpty 返回第一个非空字符串
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// fileEntries 读取日志文件（可为软链接），返回各行的 "级别:消息"
+func fileEntries(t *testing.T, path string) []string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var entries []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var line struct {
+			Level string `json:"level"`
+			Msg   string `json:"msg"`
+		}
+		if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+			t.Fatalf("%s: %v: %q", path, err, sc.Text())
+		}
+		entries = append(entries, line.Level+":"+line.Msg)
+	}
+	return entries
+}
+
+// logLevels 依次记录各级别的日志并关闭 logger
+func logLevels(t *testing.T, l *Logger) {
+	t.Helper()
+	l.Debug("d")
+	l.Info("i")
+	l.Warn("w")
+	l.Error("e")
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestErrorFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		levels    []string
+		wantError []string
+	}{
+		{"default levels", nil, []string{"warn:w", "error:e"}},
+		{"custom levels", []string{"info", "error"}, []string{"info:i", "error:e"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			l, err := New(
+				WithConsole(false, false),
+				WithFile(true, dir, "app"),
+				WithErrorFile(true, tt.levels...),
+				WithStacktrace(false, "", 0),
+			)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			logLevels(t, l)
+
+			// 分流的日志仍写入主文件，低于全局级别的日志两者都不写
+			if got, want := fileEntries(t, filepath.Join(dir, "app.log")), []string{"info:i", "warn:w", "error:e"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("app.log = %q, want %q", got, want)
+			}
+			if got := fileEntries(t, filepath.Join(dir, "app.error.log")); !reflect.DeepEqual(got, tt.wantError) {
+				t.Errorf("app.error.log = %q, want %q", got, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestFileRoutes(t *testing.T) {
+	mainDir, routeDir := t.TempDir(), t.TempDir()
+	l, err := New(
+		WithConsole(false, false),
+		WithFile(false, "", ""),
+		WithLevel("debug"),
+		WithOutputs(OutputConfig{
+			Type:     OutputFile,
+			MinLevel: "info",
+			File: FileOutputConfig{
+				Dir:      mainDir,
+				Filename: "svc",
+				Pattern:  "%Y%m%d",
+				Routes: []FileRouteConfig{
+					// 只设置目录，文件名与模式沿用主文件
+					{Levels: []string{"error"}, File: FileOutputConfig{Dir: routeDir}},
+					{Levels: []string{"debug", "warn"}, File: FileOutputConfig{Filename: "audit"}},
+				},
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	logLevels(t, l)
+
+	want := map[string][]string{
+		filepath.Join(mainDir, "svc.log"):        {"info:i", "warn:w", "error:e"},
+		filepath.Join(routeDir, "svc.error.log"): {"error:e"},
+		// 路由同样受输出级别区间限制
+		filepath.Join(mainDir, "audit.log"): {"warn:w"},
+	}
+	for path, entries := range want {
+		if got := fileEntries(t, path); !reflect.DeepEqual(got, entries) {
+			t.Errorf("%s = %q, want %q", path, got, entries)
+		}
+	}
+
+	// 文件名模式沿用主文件
+	if matches, _ := filepath.Glob(filepath.Join(routeDir, "svc.error.2*.log")); len(matches) != 1 {
+		t.Errorf("route files = %q, want one file named by the parent pattern", matches)
+	}
+}
+
+func TestInheritFileConfig(t *testing.T) {
+	parent := FileOutputConfig{
+		Dir:           "logs",
+		Filename:      "app",
+		Pattern:       "%Y%m%d",
+		MaxAge:        7,
+		RotationTime:  24,
+		RotationSize:  100,
+		RotationCount: 10,
+		MaxTotalSize:  1024,
+		Compress:      true,
+		Routes:        []FileRouteConfig{{Levels: []string{"error"}}},
+	}
+	child := FileOutputConfig{Dir: "errors", MaxAge: 30, RotationCount: 3}
+
+	got := inheritFileConfig(child, parent)
+	want := FileOutputConfig{
+		Dir:           "errors",
+		Filename:      "", // 由调用方补全为 "<主文件名>.error"
+		Pattern:       "%Y%m%d",
+		MaxAge:        30,
+		RotationTime:  24,
+		RotationSize:  100,
+		RotationCount: 3,
+		MaxTotalSize:  1024,
+		Compress:      true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("inherited = %+v\nwant %+v", got, want)
+	}
+}
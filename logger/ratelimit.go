@@ -0,0 +1,325 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	defaultRateLimitWindow  = 60
+	defaultRateLimitBudget  = 100
+	defaultRateLimitMaxKeys = 10000
+)
+
+// RateLimitConfig 限流配置。相同消息（及 KeyFields 取值相同）的日志在一个窗口内超出预算后被抑制，
+// 窗口结束时输出一条汇总，如 "suppressed 1532 similar entries"。DPanic 及以上级别不限流
+type RateLimitConfig struct {
+	Enabled   bool           `json:"enabled" yaml:"enabled"`       // 是否启用限流
+	Window    int            `json:"window" yaml:"window"`         // 窗口长度(秒)，默认 60
+	KeyFields []string       `json:"key_fields" yaml:"key_fields"` // 除消息外参与分组的字段，如 ["user_id", "path"]
+	Budgets   map[string]int `json:"budgets" yaml:"budgets"`       // 各级别每个窗口允许的条数，如 {"info": 100}；为空时 debug ~ error 均为 100，未列出的级别不限流
+	MaxKeys   int            `json:"max_keys" yaml:"max_keys"`     // 同时跟踪的分组上限，默认 10000，超出后新分组不限流
+}
+
+// rateEntry 一个分组在当前窗口内的计数
+type rateEntry struct {
+	start      time.Time // 窗口开始时间
+	count      int
+	suppressed int
+	first      time.Time // 第一条被抑制日志的时间
+	last       time.Time // 最后一条被抑制日志的时间
+
+	core   zapcore.Core // 写汇总用的下游 core，带有第一条日志的 With 字段
+	ent    zapcore.Entry
+	fields []zapcore.Field // 分组字段
+}
+
+// rateLimiter 限流状态，由同一 logger 的所有 rateLimitCore 共享
+type rateLimiter struct {
+	mu      sync.Mutex
+	window  time.Duration
+	budgets [zapcore.FatalLevel - zapcore.DebugLevel + 1]int // -1 表示不限流
+	keys    map[string]bool
+	maxKeys int
+	entries map[string]*rateEntry
+	clock   zapcore.Clock // 汇总的时间与定期检查，日志本身的时间由 logger 的时钟决定
+
+	suppressed uint64 // 累计被抑制的条数
+	done       chan struct{}
+	exited     chan struct{}
+	closeOnce  sync.Once
+}
+
+func newRateLimiter(cfg *RateLimitConfig, clock zapcore.Clock) (*rateLimiter, error) {
+	r := &rateLimiter{
+		window:  time.Duration(cfg.Window) * time.Second,
+		keys:    make(map[string]bool, len(cfg.KeyFields)),
+		maxKeys: cfg.MaxKeys,
+		entries: make(map[string]*rateEntry),
+		clock:   clock,
+		done:    make(chan struct{}),
+		exited:  make(chan struct{}),
+	}
+	if r.window <= 0 {
+		r.window = defaultRateLimitWindow * time.Second
+	}
+	if r.maxKeys <= 0 {
+		r.maxKeys = defaultRateLimitMaxKeys
+	}
+	for _, k := range cfg.KeyFields {
+		r.keys[k] = true
+	}
+
+	for i := range r.budgets {
+		r.budgets[i] = -1
+	}
+	if len(cfg.Budgets) == 0 {
+		for lvl := zapcore.DebugLevel; lvl <= zapcore.ErrorLevel; lvl++ {
+			r.budgets[lvl-zapcore.DebugLevel] = defaultRateLimitBudget
+		}
+	}
+	for level, budget := range cfg.Budgets {
+		lvl, err := parseLevel(level)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit level %s: %w", level, err)
+		}
+		if budget < 0 {
+			return nil, fmt.Errorf("invalid rate limit budget for %s: %d", level, budget)
+		}
+		if lvl < zapcore.DPanicLevel {
+			r.budgets[lvl-zapcore.DebugLevel] = budget
+		}
+	}
+
+	go r.run()
+	return r, nil
+}
+
+// budget 返回级别的预算，-1 表示不限流
+func (r *rateLimiter) budget(lvl zapcore.Level) int {
+	if lvl < zapcore.DebugLevel || lvl >= zapcore.DPanicLevel {
+		return -1
+	}
+	return r.budgets[lvl-zapcore.DebugLevel]
+}
+
+// allow 判断日志是否放行，被抑制时计数
+func (r *rateLimiter) allow(key string, core zapcore.Core, ent zapcore.Entry, keyFields []zapcore.Field) bool {
+	budget := r.budget(ent.Level)
+	if budget < 0 {
+		return true
+	}
+
+	r.mu.Lock()
+	e, ok := r.entries[key]
+	if ok && !ent.Time.Before(e.start.Add(r.window)) {
+		// 窗口已结束，先输出上一窗口的汇总
+		summary := r.takeSummary(e)
+		delete(r.entries, key)
+		ok = false
+		if summary != nil {
+			r.mu.Unlock()
+			summary()
+			r.mu.Lock()
+			e, ok = r.entries[key]
+		}
+	}
+	if !ok {
+		if len(r.entries) >= r.maxKeys {
+			r.mu.Unlock()
+			return true
+		}
+		e = &rateEntry{start: ent.Time, core: core, ent: ent, fields: keyFields}
+		r.entries[key] = e
+	}
+
+	e.count++
+	if e.count <= budget {
+		r.mu.Unlock()
+		return true
+	}
+	if e.suppressed == 0 {
+		e.first = ent.Time
+	}
+	e.suppressed++
+	e.last = ent.Time
+	r.suppressed++
+	r.mu.Unlock()
+	return false
+}
+
+// takeSummary 取出分组的汇总并清零，没有被抑制的日志时返回 nil。调用方需持有锁
+func (r *rateLimiter) takeSummary(e *rateEntry) func() {
+	if e.suppressed == 0 {
+		return nil
+	}
+
+	n, first, last := e.suppressed, e.first, e.last
+	core := e.core
+	ent := zapcore.Entry{
+		Level:      e.ent.Level,
+		Time:       r.clock.Now(),
+		LoggerName: e.ent.LoggerName,
+		Message:    "suppressed " + strconv.Itoa(n) + " similar entries",
+	}
+	fields := make([]zapcore.Field, 0, len(e.fields)+4)
+	fields = append(fields,
+		zap.String("suppressed_msg", e.ent.Message),
+		zap.Int("suppressed", n),
+		zap.Time("first_suppressed", first),
+		zap.Time("last_suppressed", last),
+	)
+	fields = append(fields, e.fields...)
+
+	e.suppressed = 0
+	return func() {
+		_ = writeChecked(core, ent, fields)
+	}
+}
+
+// flush 输出已结束窗口的汇总并清理空闲分组，all 为 true 时输出全部汇总
+func (r *rateLimiter) flush(now time.Time, all bool) {
+	var summaries []func()
+
+	r.mu.Lock()
+	for key, e := range r.entries {
+		if !all && now.Before(e.start.Add(r.window)) {
+			continue
+		}
+		if summary := r.takeSummary(e); summary != nil {
+			summaries = append(summaries, summary)
+		}
+		delete(r.entries, key)
+	}
+	r.mu.Unlock()
+
+	for _, summary := range summaries {
+		summary()
+	}
+}
+
+// run 后台协程：定期输出窗口已结束的汇总
+func (r *rateLimiter) run() {
+	defer close(r.exited)
+
+	interval := r.window / 4
+	if interval < 100*time.Millisecond {
+		interval = 100 * time.Millisecond
+	}
+	ticker := r.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+			r.flush(r.clock.Now(), false)
+		}
+	}
+}
+
+// Close 停止后台协程并输出所有未输出的汇总
+func (r *rateLimiter) Close() error {
+	r.closeOnce.Do(func() {
+		close(r.done)
+		<-r.exited
+		r.flush(r.clock.Now(), true)
+	})
+	return nil
+}
+
+// suppressedCount 返回累计被抑制的条数
+func (r *rateLimiter) suppressedCount() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.suppressed
+}
+
+// rateLimitCore 按消息与分组字段限流
+type rateLimitCore struct {
+	zapcore.Core
+	limiter *rateLimiter
+	context string          // With 字段中分组字段的取值
+	fields  []zapcore.Field // With 字段中的分组字段
+}
+
+func (c *rateLimitCore) With(fields []zapcore.Field) zapcore.Core {
+	child := &rateLimitCore{
+		Core:    c.Core.With(fields),
+		limiter: c.limiter,
+		context: c.context,
+		fields:  c.fields,
+	}
+	var b strings.Builder
+	b.WriteString(c.context)
+	for _, f := range fields {
+		if c.limiter.keys[f.Key] {
+			writeKeyField(&b, f)
+			child.fields = append(child.fields[:len(child.fields):len(child.fields)], f)
+		}
+	}
+	child.context = b.String()
+	return child
+}
+
+func (c *rateLimitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *rateLimitCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if c.limiter.budget(ent.Level) < 0 {
+		return writeChecked(c.Core, ent, fields)
+	}
+
+	var b strings.Builder
+	b.WriteString(ent.LoggerName)
+	b.WriteByte(0)
+	b.WriteString(ent.Message)
+	b.WriteByte(0)
+	b.WriteString(c.context)
+
+	keyFields := c.fields
+	for _, f := range fields {
+		if c.limiter.keys[f.Key] {
+			writeKeyField(&b, f)
+			keyFields = append(keyFields[:len(keyFields):len(keyFields)], f)
+		}
+	}
+
+	if !c.limiter.allow(b.String(), c.Core, ent, keyFields) {
+		return nil
+	}
+	return writeChecked(c.Core, ent, fields)
+}
+
+// writeKeyField 将分组字段写入 key
+func writeKeyField(b *strings.Builder, f zapcore.Field) {
+	b.WriteString(f.Key)
+	b.WriteByte('=')
+	switch f.Type {
+	case zapcore.StringType:
+		b.WriteString(f.String)
+	case zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type,
+		zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type,
+		zapcore.BoolType, zapcore.DurationType:
+		b.WriteString(strconv.FormatInt(f.Integer, 10))
+	default:
+		fmt.Fprint(b, materialize(f))
+	}
+	b.WriteByte(0)
+}
+
+// SuppressedEntries 返回被限流抑制的日志条数（含重新加载前的限流器），未启用限流时为 0
+func (l *Logger) SuppressedEntries() uint64 {
+	return l.sinks.suppressed()
+}
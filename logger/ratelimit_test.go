@@ -0,0 +1,239 @@
+package logger
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// fakeClock 手动推进的时钟，用于日志时间与限流窗口
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// NewTicker 返回不会触发的 ticker，窗口结束由测试推进时钟并调用 flush
+func (c *fakeClock) NewTicker(time.Duration) *time.Ticker {
+	t := time.NewTicker(time.Hour)
+	t.Stop()
+	return t
+}
+
+// newLimitedLogger 创建只有限流层与 observer 的 logger
+func newLimitedLogger(t *testing.T, cfg RateLimitConfig) (*zap.Logger, *observer.ObservedLogs, *fakeClock, *rateLimiter) {
+	t.Helper()
+	obs, logs := observer.New(zapcore.DebugLevel)
+	clock := newFakeClock()
+	r, err := newRateLimiter(&cfg, clock)
+	if err != nil {
+		t.Fatalf("newRateLimiter: %v", err)
+	}
+	t.Cleanup(func() { _ = r.Close() })
+	return zap.New(&rateLimitCore{Core: obs, limiter: r}, zap.WithClock(clock)), logs, clock, r
+}
+
+func messages(logs *observer.ObservedLogs) []string {
+	var msgs []string
+	for _, e := range logs.All() {
+		msgs = append(msgs, e.Message)
+	}
+	return msgs
+}
+
+func TestRateLimitBudget(t *testing.T) {
+	l, logs, _, r := newLimitedLogger(t, RateLimitConfig{Budgets: map[string]int{"info": 2}})
+
+	for i := 0; i < 5; i++ {
+		l.Info("retrying")
+	}
+	// 未列出的级别不限流
+	for i := 0; i < 3; i++ {
+		l.Warn("slow")
+	}
+
+	if n := logs.FilterMessage("retrying").Len(); n != 2 {
+		t.Errorf("retrying written %d times, want 2", n)
+	}
+	if n := logs.FilterMessage("slow").Len(); n != 3 {
+		t.Errorf("slow written %d times, want 3", n)
+	}
+	if n := r.suppressedCount(); n != 3 {
+		t.Errorf("suppressed = %d, want 3", n)
+	}
+}
+
+func TestRateLimitSeparateKeys(t *testing.T) {
+	l, logs, _, r := newLimitedLogger(t, RateLimitConfig{
+		KeyFields: []string{"user"},
+		Budgets:   map[string]int{"info": 1},
+	})
+
+	for i := 0; i < 2; i++ {
+		l.Info("login", zap.String("user", "alice"))
+		l.Info("login", zap.String("user", "bob"))
+		l.With(zap.String("user", "carol")).Info("login")
+		l.Info("logout", zap.String("user", "alice"))
+		l.Named("admin").Info("login", zap.String("user", "alice"))
+	}
+
+	// 消息、分组字段或 logger 名称不同的日志各有独立的预算
+	if n := logs.FilterMessage("login").Len(); n != 4 {
+		t.Errorf("login written %d times, want 4", n)
+	}
+	if n := logs.FilterMessage("logout").Len(); n != 1 {
+		t.Errorf("logout written %d times, want 1", n)
+	}
+	if n := r.suppressedCount(); n != 5 {
+		t.Errorf("suppressed = %d, want 5", n)
+	}
+}
+
+func TestRateLimitWindowReset(t *testing.T) {
+	l, logs, clock, _ := newLimitedLogger(t, RateLimitConfig{
+		Window:    60,
+		KeyFields: []string{"user"},
+		Budgets:   map[string]int{"warn": 2},
+	})
+	start := clock.Now()
+
+	for i := 0; i < 5; i++ {
+		l.Warn("denied", zap.String("user", "alice"), zap.Int("attempt", i))
+		clock.Advance(time.Second)
+	}
+
+	// 窗口结束后的第一条日志先触发上一窗口的汇总，随后重新计数
+	clock.Advance(time.Minute)
+	l.Warn("denied", zap.String("user", "alice"), zap.Int("attempt", 5))
+	l.Warn("denied", zap.String("user", "alice"), zap.Int("attempt", 6))
+
+	got := messages(logs)
+	want := []string{"denied", "denied", "suppressed 3 similar entries", "denied", "denied"}
+	if len(got) != len(want) {
+		t.Fatalf("messages = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("messages = %q, want %q", got, want)
+		}
+	}
+
+	summary := logs.All()[2]
+	if summary.Level != zapcore.WarnLevel || !summary.Time.Equal(start.Add(65*time.Second)) {
+		t.Errorf("summary level = %s, time = %s", summary.Level, summary.Time)
+	}
+	fields := summary.ContextMap()
+	wantFields := map[string]interface{}{
+		"suppressed_msg":   "denied",
+		"suppressed":       int64(3),
+		"first_suppressed": start.Add(2 * time.Second),
+		"last_suppressed":  start.Add(4 * time.Second),
+		"user":             "alice",
+	}
+	if len(fields) != len(wantFields) {
+		t.Errorf("summary fields = %v, want %v", fields, wantFields)
+	}
+	for k, v := range wantFields {
+		if tv, ok := v.(time.Time); ok {
+			if ft, ok := fields[k].(time.Time); !ok || !ft.Equal(tv) {
+				t.Errorf("summary %s = %v, want %v", k, fields[k], v)
+			}
+			continue
+		}
+		if fields[k] != v {
+			t.Errorf("summary %s = %v, want %v", k, fields[k], v)
+		}
+	}
+}
+
+func TestRateLimitFlushEndedWindows(t *testing.T) {
+	l, logs, clock, r := newLimitedLogger(t, RateLimitConfig{Window: 10, Budgets: map[string]int{"info": 1}})
+
+	l.Info("a")
+	l.Info("a")
+	l.Info("b") // 未超出预算，不产生汇总
+
+	// 窗口未结束时不输出
+	clock.Advance(5 * time.Second)
+	r.flush(clock.Now(), false)
+	if n := logs.FilterMessageSnippet("suppressed").Len(); n != 0 {
+		t.Fatalf("got %d summaries before the window ended", n)
+	}
+
+	clock.Advance(5 * time.Second)
+	r.flush(clock.Now(), false)
+	summaries := logs.FilterMessage("suppressed 1 similar entries").All()
+	if len(summaries) != 1 || summaries[0].ContextMap()["suppressed_msg"] != "a" {
+		t.Errorf("summaries = %v", summaries)
+	}
+
+	// 分组已清理，之后的日志重新计数
+	l.Info("a")
+	if n := logs.FilterMessage("a").Len(); n != 2 {
+		t.Errorf("a written %d times, want 2", n)
+	}
+}
+
+func TestRateLimitCloseFlushesSummaries(t *testing.T) {
+	l, logs, _, r := newLimitedLogger(t, RateLimitConfig{Budgets: map[string]int{"debug": 1}})
+
+	for i := 0; i < 4; i++ {
+		l.Debug("poll")
+	}
+	_ = r.Close()
+
+	if n := logs.FilterMessage("suppressed 3 similar entries").Len(); n != 1 {
+		t.Errorf("got %d summaries after Close, want 1: %q", n, messages(logs))
+	}
+}
+
+func TestRateLimitSuppressedEntries(t *testing.T) {
+	obs, logs := observer.New(zapcore.DebugLevel)
+	l, err := New(
+		WithFile(false, "", ""),
+		WithConsole(false, false),
+		WithCore(obs),
+		WithRateLimit(RateLimitConfig{Enabled: true, Budgets: map[string]int{"info": 1}}),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		l.Info("busy")
+	}
+	if n := l.SuppressedEntries(); n != 2 {
+		t.Errorf("SuppressedEntries = %d, want 2", n)
+	}
+	_ = l.Close()
+	if n := logs.FilterMessage("suppressed 2 similar entries").Len(); n != 1 {
+		t.Errorf("got %d summaries after Close, want 1", n)
+	}
+}
+
+func TestRateLimitInvalidConfig(t *testing.T) {
+	for _, budgets := range []map[string]int{{"loud": 1}, {"info": -1}} {
+		if _, err := newRateLimiter(&RateLimitConfig{Budgets: budgets}, newFakeClock()); err == nil {
+			t.Errorf("budgets %v: expected error", budgets)
+		}
+	}
+}
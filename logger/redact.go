@@ -0,0 +1,323 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 常用的消息脱敏正则，可直接放入 RedactionConfig.Patterns
+const (
+	PatternCardNumber = `\b(?:\d[ -]?){12,18}\d\b`
+	PatternEmail      = `[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`
+	PatternBearer     = `(?i)bearer\s+[A-Za-z0-9\-._~+/]+=*`
+)
+
+// 字段脱敏方式
+const (
+	RedactMask = "mask" // 替换为 Mask 文本
+	RedactHash = "hash" // 替换为 sha256 摘要前缀，便于关联同一值
+)
+
+const defaultRedactMask = "***"
+
+// RedactionConfig 脱敏配置，对所有输出统一生效
+type RedactionConfig struct {
+	Keys     []string `json:"keys" yaml:"keys"`         // 需脱敏的字段名，不区分大小写，支持 glob，如 "password"、"*token*"
+	Mode     string   `json:"mode" yaml:"mode"`         // 字段脱敏方式: mask(默认), hash
+	Mask     string   `json:"mask" yaml:"mask"`         // 替换文本，默认 "***"
+	Patterns []string `json:"patterns" yaml:"patterns"` // 对消息与字符串值做替换的正则，如 PatternCardNumber、PatternEmail
+}
+
+// enabled 是否配置了任何脱敏规则
+func (c *RedactionConfig) enabled() bool {
+	return len(c.Keys) > 0 || len(c.Patterns) > 0
+}
+
+// redactor 按字段名与正则脱敏
+type redactor struct {
+	keys     []string // 小写的字段名或 glob
+	hash     bool
+	mask     string
+	patterns []*regexp.Regexp
+
+	keyCache sync.Map // 字段名 -> 是否需要脱敏
+}
+
+func newRedactor(cfg *RedactionConfig) (*redactor, error) {
+	r := &redactor{
+		mask: firstNonEmpty(cfg.Mask, defaultRedactMask),
+	}
+
+	switch strings.ToLower(firstNonEmpty(cfg.Mode, RedactMask)) {
+	case RedactMask:
+	case RedactHash:
+		r.hash = true
+	default:
+		return nil, fmt.Errorf("unknown redaction mode: %q", cfg.Mode)
+	}
+
+	for _, key := range cfg.Keys {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			continue
+		}
+		if _, err := path.Match(key, ""); err != nil {
+			return nil, fmt.Errorf("invalid redaction key pattern %q: %w", key, err)
+		}
+		r.keys = append(r.keys, key)
+	}
+
+	for _, p := range cfg.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// matchKey 判断字段名是否需要脱敏
+func (r *redactor) matchKey(key string) bool {
+	if len(r.keys) == 0 {
+		return false
+	}
+	if v, ok := r.keyCache.Load(key); ok {
+		return v.(bool)
+	}
+
+	lower := strings.ToLower(key)
+	matched := false
+	for _, pattern := range r.keys {
+		if pattern == lower {
+			matched = true
+			break
+		}
+		if ok, _ := path.Match(pattern, lower); ok {
+			matched = true
+			break
+		}
+	}
+	r.keyCache.Store(key, matched)
+	return matched
+}
+
+// scrub 对文本做正则替换
+func (r *redactor) scrub(s string) (string, bool) {
+	changed := false
+	for _, re := range r.patterns {
+		if re.MatchString(s) {
+			s = re.ReplaceAllLiteralString(s, r.mask)
+			changed = true
+		}
+	}
+	return s, changed
+}
+
+// conceal 生成字段值的替换文本
+func (r *redactor) conceal(v interface{}) string {
+	if !r.hash {
+		return r.mask
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprint(v)))
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+// fields 脱敏字段列表，没有变化时返回原切片
+func (r *redactor) fields(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i := range fields {
+		replaced, changed := r.field(fields[i])
+		if !changed {
+			if out != nil {
+				out = append(out, fields[i])
+			}
+			continue
+		}
+		if out == nil {
+			out = make([]zapcore.Field, 0, len(fields)+len(replaced))
+			out = append(out, fields[:i]...)
+		}
+		out = append(out, replaced...)
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// field 脱敏单个字段，Inline 字段可能展开为多个字段
+func (r *redactor) field(f zapcore.Field) ([]zapcore.Field, bool) {
+	switch f.Type {
+	case zapcore.NamespaceType, zapcore.SkipType:
+		return nil, false
+	case zapcore.InlineMarshalerType:
+		return r.inline(f)
+	}
+
+	if r.matchKey(f.Key) {
+		if !r.hash {
+			return []zapcore.Field{zap.String(f.Key, r.mask)}, true
+		}
+		return []zapcore.Field{zap.String(f.Key, r.conceal(materialize(f)))}, true
+	}
+
+	switch f.Type {
+	case zapcore.StringType:
+		if s, ok := r.scrub(f.String); ok {
+			return []zapcore.Field{zap.String(f.Key, s)}, true
+		}
+	case zapcore.ByteStringType:
+		if b, isBytes := f.Interface.([]byte); isBytes {
+			if s, ok := r.scrub(string(b)); ok {
+				return []zapcore.Field{zap.String(f.Key, s)}, true
+			}
+		}
+	case zapcore.ErrorType, zapcore.StringerType:
+		if len(r.patterns) == 0 {
+			return nil, false
+		}
+		if s, isString := materialize(f).(string); isString {
+			if scrubbed, ok := r.scrub(s); ok {
+				return []zapcore.Field{zap.String(f.Key, scrubbed)}, true
+			}
+		}
+	case zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType, zapcore.ReflectType:
+		if v, ok := r.walk(materialize(f)); ok {
+			return []zapcore.Field{zap.Any(f.Key, v)}, true
+		}
+	}
+	return nil, false
+}
+
+// inline 处理 zap.Inline 字段：展开为普通字段后逐个脱敏
+func (r *redactor) inline(f zapcore.Field) ([]zapcore.Field, bool) {
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+
+	v, changed := r.walk(enc.Fields)
+	if !changed {
+		return nil, false
+	}
+
+	m := v.(map[string]interface{})
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]zapcore.Field, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, zap.Any(k, m[k]))
+	}
+	return out, true
+}
+
+// walk 递归脱敏已展开的值，没有变化时返回 false
+func (r *redactor) walk(v interface{}) (interface{}, bool) {
+	switch val := v.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64,
+		uintptr, float32, float64, complex64, complex128:
+		return v, false
+	case string:
+		return r.scrub(val)
+	case map[string]interface{}:
+		var out map[string]interface{}
+		for k, item := range val {
+			var replaced interface{}
+			changed := false
+			if r.matchKey(k) {
+				replaced, changed = r.conceal(item), true
+			} else {
+				replaced, changed = r.walk(item)
+			}
+			if !changed {
+				continue
+			}
+			if out == nil {
+				out = make(map[string]interface{}, len(val))
+				for k2, v2 := range val {
+					out[k2] = v2
+				}
+			}
+			out[k] = replaced
+		}
+		if out == nil {
+			return v, false
+		}
+		return out, true
+	case []interface{}:
+		var out []interface{}
+		for i, item := range val {
+			replaced, changed := r.walk(item)
+			if !changed {
+				continue
+			}
+			if out == nil {
+				out = make([]interface{}, len(val))
+				copy(out, val)
+			}
+			out[i] = replaced
+		}
+		if out == nil {
+			return v, false
+		}
+		return out, true
+	default:
+		// 结构体、自定义 map 等通过 JSON 转为通用结构后再处理
+		data, err := json.Marshal(v)
+		if err != nil {
+			return v, false
+		}
+		var generic interface{}
+		if err := json.Unmarshal(data, &generic); err != nil {
+			return v, false
+		}
+		return r.walk(generic)
+	}
+}
+
+// materialize 将字段编码为 Go 值：对象为 map，数组为切片，其余为基础类型
+func materialize(f zapcore.Field) interface{} {
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+	return enc.Fields[f.Key]
+}
+
+// redactCore 对消息与字段脱敏后再交给下游 core
+type redactCore struct {
+	zapcore.Core
+	r *redactor
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{
+		Core: c.Core.With(c.r.fields(fields)),
+		r:    c.r,
+	}
+}
+
+func (c *redactCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *redactCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if msg, ok := c.r.scrub(ent.Message); ok {
+		ent.Message = msg
+	}
+	return writeChecked(c.Core, ent, c.r.fields(fields))
+}
@@ -0,0 +1,264 @@
+package logger_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/constellation39/framework/logger"
+	"github.com/constellation39/framework/logger/loggertest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// credentials 以 zap.Object 记录的对象
+type credentials struct {
+	user     string
+	password string
+}
+
+func (c credentials) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("user", c.user)
+	enc.AddString("password", c.password)
+	return nil
+}
+
+// onlyEntry 返回唯一一条日志的全部字段
+func onlyEntry(t *testing.T, r *loggertest.Recorder) (string, map[string]interface{}) {
+	t.Helper()
+	entries := r.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	return entries[0].Message, entries[0].ContextMap()
+}
+
+func TestRedactKeyGlobs(t *testing.T) {
+	r := loggertest.New(t, logger.WithRedactKeys("password", "*token*"))
+
+	r.Info("login",
+		zap.String("Password", "hunter2"), // 不区分大小写
+		zap.String("access_token", "abc"),
+		zap.Int("token_ttl", 3600), // 非字符串值同样替换
+		zap.String("user", "alice"),
+	)
+
+	_, fields := onlyEntry(t, r)
+	want := map[string]interface{}{
+		"Password":     "***",
+		"access_token": "***",
+		"token_ttl":    "***",
+		"user":         "alice",
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields = %v, want %v", fields, want)
+	}
+}
+
+func TestRedactHashMode(t *testing.T) {
+	r := loggertest.New(t, logger.WithRedaction(logger.RedactionConfig{
+		Keys: []string{"email"},
+		Mode: logger.RedactHash,
+	}))
+
+	r.Info("first", zap.String("email", "alice@example.com"))
+	r.Info("second", zap.String("email", "alice@example.com"))
+
+	sum := sha256.Sum256([]byte("alice@example.com"))
+	want := "sha256:" + hex.EncodeToString(sum[:8])
+	// 相同的值得到相同的摘要，便于关联
+	for _, e := range r.Entries() {
+		if got := e.ContextMap()["email"]; got != want {
+			t.Errorf("%s: email = %v, want %s", e.Message, got, want)
+		}
+	}
+}
+
+func TestRedactCustomMask(t *testing.T) {
+	r := loggertest.New(t, logger.WithRedaction(logger.RedactionConfig{
+		Keys: []string{"secret"},
+		Mask: "[REDACTED]",
+	}))
+
+	r.Info("msg", zap.String("secret", "s3cr3t"))
+
+	if _, fields := onlyEntry(t, r); fields["secret"] != "[REDACTED]" {
+		t.Errorf("secret = %v, want [REDACTED]", fields["secret"])
+	}
+}
+
+func TestRedactPatterns(t *testing.T) {
+	r := loggertest.New(t, logger.WithRedactPatterns(logger.PatternCardNumber, logger.PatternEmail, logger.PatternBearer))
+
+	r.Info("charge 4111 1111 1111 1111 for bob@example.com",
+		zap.String("header", "Bearer eyJhbGciOi.payload.sig"),
+		zap.ByteString("raw", []byte("card=4111111111111111")),
+		zap.String("note", "nothing to hide"),
+	)
+
+	msg, fields := onlyEntry(t, r)
+	if msg != "charge *** for ***" {
+		t.Errorf("message = %q", msg)
+	}
+	want := map[string]interface{}{
+		"header": "***",
+		"raw":    "card=***",
+		"note":   "nothing to hide",
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields = %v, want %v", fields, want)
+	}
+}
+
+func TestRedactNestedValues(t *testing.T) {
+	r := loggertest.New(t,
+		logger.WithRedactKeys("password", "authorization"),
+		logger.WithRedactPatterns(logger.PatternEmail),
+	)
+
+	r.Info("request",
+		zap.Object("creds", credentials{user: "alice", password: "hunter2"}),
+		zap.Any("req", map[string]interface{}{
+			"headers":  map[string]interface{}{"Authorization": "Bearer abc", "Accept": "*/*"},
+			"contacts": []interface{}{"bob@example.com", 42},
+		}),
+		zap.Any("user", struct {
+			Name     string `json:"name"`
+			Password string `json:"password"`
+		}{"alice", "hunter2"}),
+	)
+
+	_, fields := onlyEntry(t, r)
+	want := map[string]interface{}{
+		"creds": map[string]interface{}{"user": "alice", "password": "***"},
+		"req": map[string]interface{}{
+			"headers":  map[string]interface{}{"Authorization": "***", "Accept": "*/*"},
+			"contacts": []interface{}{"***", 42},
+		},
+		"user": map[string]interface{}{"name": "alice", "password": "***"},
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields = %#v\nwant %#v", fields, want)
+	}
+}
+
+func TestRedactInline(t *testing.T) {
+	r := loggertest.New(t, logger.WithRedactKeys("password"))
+
+	r.Info("inline", zap.Inline(credentials{user: "alice", password: "hunter2"}))
+
+	_, fields := onlyEntry(t, r)
+	want := map[string]interface{}{"user": "alice", "password": "***"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields = %v, want %v", fields, want)
+	}
+}
+
+func TestRedactWithFields(t *testing.T) {
+	r := loggertest.New(t, logger.WithRedactKeys("api_key"), logger.WithRedactPatterns(logger.PatternEmail))
+
+	child := r.With(zap.String("api_key", "k-123"), zap.String("owner", "alice@example.com"))
+	child.Info("bound")
+	r.Named("db").With(zap.String("api_key", "k-456")).Info("named")
+
+	for _, e := range r.Entries() {
+		fields := e.ContextMap()
+		if fields["api_key"] != "***" {
+			t.Errorf("%s: api_key = %v, want ***", e.Message, fields["api_key"])
+		}
+		if owner, ok := fields["owner"]; ok && owner != "***" {
+			t.Errorf("%s: owner = %v, want ***", e.Message, owner)
+		}
+	}
+}
+
+func TestRedactAppliesToEveryOutput(t *testing.T) {
+	dir := t.TempDir()
+	r := loggertest.New(t,
+		logger.WithFile(true, dir, "app"),
+		logger.WithRedactKeys("password"),
+		logger.WithRedactPatterns(logger.PatternEmail),
+	)
+
+	var (
+		mu     sync.Mutex
+		hooked []zapcore.Field
+		msgs   []string
+	)
+	done := make(chan struct{}, 1)
+	cancel := r.OnEntry(zapcore.InfoLevel, func(ent zapcore.Entry, fields []zapcore.Field) {
+		mu.Lock()
+		msgs = append(msgs, ent.Message)
+		hooked = append(hooked, fields...)
+		mu.Unlock()
+		done <- struct{}{}
+	})
+	defer cancel()
+
+	r.With(zap.String("password", "bound")).Info("mail alice@example.com", zap.String("password", "hunter2"))
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("hook not called")
+	}
+	_ = r.Sync()
+
+	// WithCore 追加的输出
+	msg, fields := onlyEntry(t, r)
+	if msg != "mail ***" || fields["password"] != "***" {
+		t.Errorf("core entry = %q %v", msg, fields)
+	}
+
+	// 回调
+	mu.Lock()
+	for _, f := range hooked {
+		if f.Key == "password" && f.String != "***" {
+			t.Errorf("hook field password = %q", f.String)
+		}
+	}
+	if len(hooked) != 2 || msgs[0] != "mail ***" {
+		t.Errorf("hook got %q with %d fields, want redacted message and 2 fields", msgs, len(hooked))
+	}
+	mu.Unlock()
+
+	// 文件输出
+	data, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	if strings.Contains(out, "hunter2") || strings.Contains(out, "bound") || strings.Contains(out, "alice@example.com") {
+		t.Errorf("file output not redacted: %s", out)
+	}
+}
+
+func TestRedactInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  logger.RedactionConfig
+	}{
+		{"mode", logger.RedactionConfig{Keys: []string{"password"}, Mode: "rot13"}},
+		{"key glob", logger.RedactionConfig{Keys: []string{"[token"}}},
+		{"pattern", logger.RedactionConfig{Patterns: []string{"("}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := logger.New(
+				logger.WithFile(false, "", ""),
+				logger.WithConsole(false, false),
+				logger.WithCore(zapcore.NewNopCore()),
+				logger.WithRedaction(tt.cfg),
+			)
+			if err == nil {
+				_ = l.Close()
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
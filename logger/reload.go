@@ -0,0 +1,278 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const defaultWatchInterval = 2 * time.Second
+
+// sinkSet 由同一份配置构建的一组输出，重新加载时整体替换
+type sinkSet struct {
+	core    zapcore.Core
+	closers []io.Closer
+	async   *asyncQueue
+	limiter *rateLimiter
+	config  *Config
+}
+
+// sinkSwitch 持有当前生效的 sinkSet。
+// 写入期间持有读锁，替换时持有写锁，旧输出在所有进行中的写入完成后才关闭
+type sinkSwitch struct {
+	mu      sync.RWMutex
+	set     atomic.Pointer[sinkSet]
+	retired atomic.Uint64 // 已替换的异步队列累计丢弃的条数
+	limited atomic.Uint64 // 已替换的限流器累计抑制的条数
+	metrics *metrics
+	hooks   *entryHooks
+}
+
+func newSinkSwitch(set *sinkSet, m *metrics, hooks *entryHooks) *sinkSwitch {
+	s := &sinkSwitch{metrics: m, hooks: hooks}
+	s.set.Store(set)
+	return s
+}
+
+func (s *sinkSwitch) current() *sinkSet {
+	return s.set.Load()
+}
+
+// swap 替换为新的输出，并刷新、关闭旧输出
+func (s *sinkSwitch) swap(set *sinkSet) error {
+	s.mu.Lock()
+	old := s.set.Swap(set)
+	s.mu.Unlock()
+
+	_ = old.core.Sync()
+	err := closeAll(old.closers)
+	if old.async != nil {
+		s.retired.Add(old.async.dropped.Load())
+	}
+	if old.limiter != nil {
+		s.limited.Add(old.limiter.suppressedCount())
+	}
+	return err
+}
+
+// close 关闭当前输出
+func (s *sinkSwitch) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return closeAll(s.current().closers)
+}
+
+// dropped 返回异步队列累计丢弃的条数
+func (s *sinkSwitch) dropped() uint64 {
+	n := s.retired.Load()
+	if q := s.current().async; q != nil {
+		n += q.dropped.Load()
+	}
+	return n
+}
+
+// suppressed 返回限流累计抑制的条数
+func (s *sinkSwitch) suppressed() uint64 {
+	n := s.limited.Load()
+	if r := s.current().limiter; r != nil {
+		n += r.suppressedCount()
+	}
+	return n
+}
+
+// sinkBound 绑定了 With 字段的某一代输出
+type sinkBound struct {
+	set  *sinkSet
+	core zapcore.Core
+}
+
+// sinkCore 将写入转发到当前生效的输出。
+// With 字段保存在本层，输出替换后在首次写入时重新绑定
+type sinkCore struct {
+	sinks  *sinkSwitch
+	fields []zapcore.Field
+	bound  atomic.Pointer[sinkBound]
+}
+
+func (c *sinkCore) coreFor(set *sinkSet) zapcore.Core {
+	if len(c.fields) == 0 {
+		return set.core
+	}
+	if b := c.bound.Load(); b != nil && b.set == set {
+		return b.core
+	}
+	core := set.core.With(c.fields)
+	c.bound.Store(&sinkBound{set: set, core: core})
+	return core
+}
+
+func (c *sinkCore) Enabled(lvl zapcore.Level) bool {
+	return c.sinks.current().core.Enabled(lvl)
+}
+
+func (c *sinkCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &sinkCore{sinks: c.sinks, fields: merged}
+}
+
+func (c *sinkCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *sinkCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.sinks.metrics.entries.inc(entryKey{level: ent.Level, logger: ent.LoggerName})
+
+	c.sinks.mu.RLock()
+	defer c.sinks.mu.RUnlock()
+
+	return writeChecked(c.coreFor(c.sinks.current()), ent, fields)
+}
+
+func (c *sinkCore) Sync() error {
+	c.sinks.mu.RLock()
+	defer c.sinks.mu.RUnlock()
+
+	return c.sinks.current().core.Sync()
+}
+
+// Reload 使用新配置重建输出并原子替换，同时更新日志级别与模块级别。
+// 配置无效时返回错误并保留原配置；CallerSkip 不会重新加载，WithCore 追加的输出会保留
+func (l *Logger) Reload(cfg *Config) error {
+	if cfg == nil {
+		return fmt.Errorf("nil config")
+	}
+	c := *cfg
+	c.optErr = nil
+	if c.cores == nil {
+		c.cores = l.sinks.current().config.cores
+	}
+	applyEnvironment(&c)
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	level, err := parseLevel(c.Level)
+	if err != nil {
+		return fmt.Errorf("invalid log level %s: %w", c.Level, err)
+	}
+	stackLevel, err := stacktraceLevel(&c)
+	if err != nil {
+		return err
+	}
+	modules, err := newModuleLevels(c.ModuleLevels)
+	if err != nil {
+		return err
+	}
+
+	set, err := buildSinks(&c, l.sinks.metrics, l.sinks.hooks)
+	if err != nil {
+		return err
+	}
+
+	l.level.SetLevel(level)
+	l.stackLevel.SetLevel(stackLevel)
+	l.modules.replace(modules)
+
+	if err := l.sinks.swap(set); err != nil {
+		return fmt.Errorf("failed to close previous outputs: %w", err)
+	}
+	return nil
+}
+
+// ReloadFile 从配置文件重新加载
+func (l *Logger) ReloadFile(path string) error {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	return l.Reload(cfg)
+}
+
+// WatchConfig 每隔 interval 检查配置文件，内容变化时重新加载。
+// 重新加载只使用文件内容，失败时保留原配置并记录错误日志。返回的函数用于停止监听
+func (l *Logger) WatchConfig(path string, interval time.Duration) (stop func(), err error) {
+	return watchConfig(path, interval, func() *Logger { return l })
+}
+
+// watchConfig 轮询配置文件，target 返回需要重新加载的 logger
+func watchConfig(path string, interval time.Duration, target func() *Logger) (func(), error) {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch config file: %w", err)
+	}
+	last, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch config file: %w", err)
+	}
+	modTime, size := info.ModTime(), info.Size()
+
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			info, err := os.Stat(path)
+			if err != nil || (info.ModTime().Equal(modTime) && info.Size() == size) {
+				continue
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			modTime, size = info.ModTime(), info.Size()
+			if bytes.Equal(data, last) {
+				continue
+			}
+			last = data
+
+			l := target()
+			if l == nil {
+				continue
+			}
+			cfg, err := parseConfig(path, data)
+			if err == nil {
+				err = l.Reload(cfg)
+			}
+			if err != nil {
+				l.Error("failed to reload logger config, keeping previous config",
+					zap.String("path", path), zap.Error(err))
+				continue
+			}
+			l.Info("logger config reloaded", zap.String("path", path))
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-exited
+		})
+	}, nil
+}
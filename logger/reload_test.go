@@ -0,0 +1,288 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// syncBuffer 并发安全的 WriteSyncer
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Sync() error {
+	return nil
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWriteErrorsReachErrorOutput(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"sync", nil},
+		{"wrapped", []Option{
+			WithRedactKeys("password"),
+			WithRateLimit(RateLimitConfig{Enabled: true}),
+		}},
+		{"async", []Option{WithAsync(true, 16, OverflowBlock, "")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{
+				WithFile(false, "", ""),
+				WithConsole(false, false),
+				WithOutputs(OutputConfig{Name: "net", Type: OutputTCP, Address: closedAddr(t)}),
+			}, tt.opts...)
+			l, err := New(opts...)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			defer l.Close()
+
+			// 回调同样经过写入链路
+			defer l.OnEntry(zapcore.InfoLevel, func(zapcore.Entry, []zapcore.Field) {})()
+
+			var errOut syncBuffer
+			if q := l.sinks.current().async; q != nil {
+				q.errOut = &errOut
+			}
+			zl := l.WithOptions(zap.ErrorOutput(&errOut))
+			zl.Info("unreachable", zap.String("password", "secret"))
+			_ = zl.Sync()
+
+			out := errOut.String()
+			if !strings.Contains(out, "write error: ") || !strings.Contains(out, "failed to dial tcp") {
+				t.Errorf("error output = %q, want the dial error", out)
+			}
+			if n := strings.Count(out, "write error: "); n != 1 {
+				t.Errorf("error reported %d times, want once: %q", n, out)
+			}
+			if !strings.Contains(metricsText(t, l), `logger_sink_write_failures_total{sink="net"} 1`) {
+				t.Errorf("write failure not counted:\n%s", metricsText(t, l))
+			}
+		})
+	}
+}
+
+// newObservedLogger 创建只输出到 observer 的 logger，测试结束时关闭
+func newObservedLogger(t *testing.T, opts ...Option) (*Logger, *observer.ObservedLogs) {
+	t.Helper()
+	obs, logs := observer.New(zapcore.DebugLevel)
+	base := []Option{WithFile(false, "", ""), WithConsole(false, false), WithCore(obs)}
+	l, err := New(append(base, opts...)...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	return l, logs
+}
+
+func TestReloadRejectsInvalidConfig(t *testing.T) {
+	l, logs := newObservedLogger(t, WithLevel("warn"))
+	before := l.sinks.current()
+
+	cfg := l.GetConfig()
+	cfg.Level = "debug"
+	cfg.Outputs = []OutputConfig{{Type: "pigeon"}}
+	if err := l.Reload(cfg); err == nil {
+		t.Fatal("expected error for unknown output type")
+	}
+
+	// 原输出与级别保持不变
+	if l.sinks.current() != before {
+		t.Error("sinks replaced by an invalid config")
+	}
+	l.Info("hidden")
+	l.Warn("kept")
+	if got := messages(logs); len(got) != 1 || got[0] != "kept" {
+		t.Errorf("messages = %q, want [kept]", got)
+	}
+}
+
+func TestReloadKeepsWithCoreOutputs(t *testing.T) {
+	l, logs := newObservedLogger(t)
+
+	// 配置文件得到的配置不含 WithCore 追加的输出
+	path := filepath.Join(t.TempDir(), "log.yaml")
+	if err := os.WriteFile(path, []byte("level: warn\nenable_file: false\nenable_console: false\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.ReloadFile(path); err != nil {
+		t.Fatalf("ReloadFile: %v", err)
+	}
+
+	l.Info("hidden")
+	l.Warn("after reload")
+	if got := messages(logs); len(got) != 1 || got[0] != "after reload" {
+		t.Errorf("messages = %q, want [after reload]", got)
+	}
+}
+
+// readJSONLines 读取目录中所有日志文件的 JSON 行
+func readJSONLines(t *testing.T, dirs ...string) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range entries {
+			if !e.Type().IsRegular() {
+				continue
+			}
+			f, err := os.Open(filepath.Join(dir, e.Name()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			sc := bufio.NewScanner(f)
+			for sc.Scan() {
+				var line map[string]interface{}
+				if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+					t.Fatalf("%s: %v: %q", e.Name(), err, sc.Text())
+				}
+				lines = append(lines, line)
+			}
+			_ = f.Close()
+		}
+	}
+	return lines
+}
+
+func TestReloadUnderConcurrentLogging(t *testing.T) {
+	dirs := []string{t.TempDir(), t.TempDir()}
+	configFor := func(dir string) *Config {
+		cfg := defaultConfig()
+		cfg.EnableConsole = false
+		cfg.LogDir = dir
+		cfg.EnableAsync = true
+		cfg.AsyncQueueSize = 64
+		return cfg
+	}
+
+	l, err := New(WithConfig(configFor(dirs[0])))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	const workers, perWorker = 8, 500
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				l.With(zap.Int("worker", w)).Info("entry", zap.String("id", fmt.Sprintf("%d-%d", w, i)))
+			}
+		}(w)
+	}
+
+	// 写入期间在两个目录之间反复切换
+	for i := 1; i <= 20; i++ {
+		if err := l.Reload(configFor(dirs[i%2])); err != nil {
+			t.Fatalf("Reload: %v", err)
+		}
+	}
+	wg.Wait()
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, line := range readJSONLines(t, dirs...) {
+		if line["msg"] != "entry" {
+			continue
+		}
+		id, _ := line["id"].(string)
+		if seen[id] {
+			t.Fatalf("duplicate entry %s", id)
+		}
+		seen[id] = true
+		if _, ok := line["worker"]; !ok {
+			t.Fatalf("entry %s lost its With field", id)
+		}
+	}
+	if len(seen) != workers*perWorker {
+		t.Errorf("got %d entries, want %d", len(seen), workers*perWorker)
+	}
+	if n := l.DroppedEntries(); n != 0 {
+		t.Errorf("DroppedEntries = %d, want 0", n)
+	}
+}
+
+// waitFor 轮询直到 cond 成立
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWatchConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.yaml")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("level: info\nenable_file: false\nenable_console: false\n")
+
+	l, logs := newObservedLogger(t, WithConfigFile(path))
+	stop, err := l.WatchConfig(path, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("WatchConfig: %v", err)
+	}
+	defer stop()
+
+	// 修改后重新加载
+	write("level: warn\nenable_file: false\nenable_console: false\n")
+	waitFor(t, "reload", func() bool { return l.Level() == zapcore.WarnLevel })
+
+	// 无效的修改被拒绝，保留原配置
+	write("level: loud\nenable_file: false\nenable_console: false\n")
+	waitFor(t, "reload error", func() bool {
+		return logs.FilterMessage("failed to reload logger config, keeping previous config").Len() == 1
+	})
+	if lvl := l.Level(); lvl != zapcore.WarnLevel {
+		t.Errorf("level = %s, want warn", lvl)
+	}
+
+	// 停止后不再重新加载
+	stop()
+	write("level: debug\nenable_file: false\nenable_console: false\n")
+	time.Sleep(50 * time.Millisecond)
+	if lvl := l.Level(); lvl != zapcore.WarnLevel {
+		t.Errorf("level after stop = %s, want warn", lvl)
+	}
+}
@@ -0,0 +1,199 @@
+package rotate
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	gzipSuffix = ".gz"
+	tmpSuffix  = ".tmp"
+)
+
+// signalMill 通知后台协程处理旧文件，已有待处理信号时直接返回
+func (w *Writer) signalMill() {
+	select {
+	case w.millCh <- struct{}{}:
+	default:
+	}
+}
+
+// millLoop 后台依次执行：崩溃恢复、压缩、清理
+func (w *Writer) millLoop() {
+	defer close(w.millDone)
+	for range w.millCh {
+		if err := w.mill(); err != nil {
+			fmt.Fprintf(os.Stderr, "[rotate] %v\n", err)
+		}
+	}
+}
+
+func (w *Writer) mill() error {
+	files, err := w.listFiles()
+	if err != nil {
+		return err
+	}
+
+	// 崩溃恢复：未完成的 .gz.tmp 直接删除（原文件仍在）；
+	// .gz 已完整落盘但原文件未删除的，删除原文件
+	compressed := make(map[string]bool)
+	for _, f := range files {
+		if f.compressed {
+			compressed[strings.TrimSuffix(f.path, gzipSuffix)] = true
+		}
+	}
+	kept := files[:0]
+	for _, f := range files {
+		switch {
+		case f.partial:
+			_ = os.Remove(f.path)
+		case !f.compressed && compressed[f.path] && !w.active(f):
+			_ = os.Remove(f.path)
+		default:
+			kept = append(kept, f)
+		}
+	}
+	files = kept
+
+	if w.opts.compress {
+		for i, f := range files {
+			// 列出文件之后可能已发生轮转，压缩前按最新状态判断
+			if f.compressed || w.active(f) {
+				continue
+			}
+			if err := compressFile(f.path); err != nil {
+				fmt.Fprintf(os.Stderr, "[rotate] compress %s: %v\n", f.path, err)
+				continue
+			}
+			files[i].path = f.path + gzipSuffix
+			files[i].compressed = true
+			if fi, err := os.Stat(files[i].path); err == nil {
+				files[i].size = fi.Size()
+			}
+		}
+	}
+
+	w.prune(files)
+	return nil
+}
+
+// active 判断文件是否为当前文件或比当前文件更新，这类文件可能正在写入，不能压缩或删除
+func (w *Writer) active(f logFile) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if f.path == w.curPath {
+		return true
+	}
+	if f.key == w.key {
+		return f.seq >= w.seq
+	}
+	return w.pattern.timed && f.time.After(w.period)
+}
+
+// prune 按数量、时长与总大小清理旧文件，当前文件不会被删除。files 需从旧到新排列
+func (w *Writer) prune(files []logFile) {
+	remove := make([]bool, len(files))
+
+	if w.opts.maxFiles > 0 && uint(len(files)) > w.opts.maxFiles {
+		excess := len(files) - int(w.opts.maxFiles)
+		for i := 0; i < len(files) && excess > 0; i++ {
+			if w.active(files[i]) {
+				continue
+			}
+			remove[i] = true
+			excess--
+		}
+	}
+
+	if w.opts.maxAge > 0 {
+		cutoff := w.opts.clock.Now().Add(-w.opts.maxAge)
+		for i, f := range files {
+			// 按最后写入时间而非时间段起点计算，时间段内最后写入的日志同样保留 maxAge
+			if f.modTime.Before(cutoff) && !w.active(f) {
+				remove[i] = true
+			}
+		}
+	}
+
+	if w.opts.maxTotalSize > 0 {
+		var total int64
+		for i := len(files) - 1; i >= 0; i-- {
+			if remove[i] {
+				continue
+			}
+			total += files[i].size
+			if total > w.opts.maxTotalSize && !w.active(files[i]) {
+				remove[i] = true
+			}
+		}
+	}
+
+	for i, f := range files {
+		if remove[i] {
+			_ = os.Remove(f.path)
+		}
+	}
+}
+
+// compressFile 将 src 压缩为 src.gz。
+// 先写入 .gz.tmp 并 fsync，再原子重命名，最后删除原文件，
+// 任一步骤中断都不会产生残缺的 .gz 或丢失原文件
+func compressFile(src string) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer in.Close()
+
+	fi, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst := src + gzipSuffix
+	tmp := dst + tmpSuffix
+
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, fi.Mode())
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", tmp, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = out.Close()
+			_ = os.Remove(tmp)
+		}
+	}()
+
+	gz := gzip.NewWriter(out)
+	gz.Name = fi.Name()
+	gz.ModTime = fi.ModTime()
+
+	if _, err = io.Copy(gz, in); err != nil {
+		return err
+	}
+	if err = gz.Close(); err != nil {
+		return err
+	}
+	if err = out.Sync(); err != nil {
+		return err
+	}
+	if err = out.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp, dst); err != nil {
+		return err
+	}
+
+	// 保留原文件的修改时间，便于按时间清理
+	_ = os.Chtimes(dst, fi.ModTime(), fi.ModTime())
+
+	_ = in.Close()
+	return os.Remove(src)
+}
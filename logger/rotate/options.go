@@ -0,0 +1,101 @@
+package rotate
+
+import "time"
+
+// Clock 时间源，测试中可替换为确定性的实现
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc 将函数适配为 Clock
+type ClockFunc func() time.Time
+
+// Now 实现 Clock
+func (f ClockFunc) Now() time.Time { return f() }
+
+// Local 使用本地时区的系统时钟
+var Local Clock = ClockFunc(time.Now)
+
+// UTC 使用 UTC 时区的系统时钟
+var UTC Clock = ClockFunc(func() time.Time { return time.Now().UTC() })
+
+// options Writer 配置
+type options struct {
+	clock        Clock
+	linkName     string
+	rotationTime time.Duration
+	rotationSize int64
+	maxFiles     uint
+	maxAge       time.Duration
+	maxTotalSize int64
+	compress     bool
+}
+
+// Option 配置选项
+type Option func(*options)
+
+// WithClock 设置时间源
+func WithClock(clock Clock) Option {
+	return func(o *options) {
+		if clock != nil {
+			o.clock = clock
+		}
+	}
+}
+
+// WithLinkName 设置指向当前文件的软链接路径
+func WithLinkName(path string) Option {
+	return func(o *options) {
+		o.linkName = path
+	}
+}
+
+// WithRotationTime 设置按时间轮转的周期，0 表示不按时间轮转
+func WithRotationTime(d time.Duration) Option {
+	return func(o *options) {
+		if d >= 0 {
+			o.rotationTime = d
+		}
+	}
+}
+
+// WithRotationSize 设置单个文件的最大字节数，0 表示不按大小轮转
+func WithRotationSize(size int64) Option {
+	return func(o *options) {
+		if size >= 0 {
+			o.rotationSize = size
+		}
+	}
+}
+
+// WithMaxFiles 设置保留的文件数量（含当前文件），0 表示不限制
+func WithMaxFiles(n uint) Option {
+	return func(o *options) {
+		o.maxFiles = n
+	}
+}
+
+// WithMaxAge 设置文件保留时长，按文件最后修改时间计算，0 表示不限制
+func WithMaxAge(d time.Duration) Option {
+	return func(o *options) {
+		if d >= 0 {
+			o.maxAge = d
+		}
+	}
+}
+
+// WithMaxTotalSize 设置所有文件（含当前文件与压缩文件）的总字节数上限，0 表示不限制
+func WithMaxTotalSize(size int64) Option {
+	return func(o *options) {
+		if size >= 0 {
+			o.maxTotalSize = size
+		}
+	}
+}
+
+// WithCompress 设置是否在轮转后以 gzip 压缩旧文件
+func WithCompress(enabled bool) Option {
+	return func(o *options) {
+		o.compress = enabled
+	}
+}
@@ -0,0 +1,229 @@
+package rotate
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 文件名模式支持的占位符：
+//
+//	%Y 四位年份   %m 月   %d 日
+//	%H 小时       %M 分钟 %S 秒
+//	%N 序号（同一时间段内按大小轮转时递增，从 1 开始）
+//	%% 字面量 %
+//
+// 模式中未包含 %N 时，会自动在扩展名之前插入 ".%N"。
+// 紧跟在 "." 之后的 %N 可以连同 "." 一起缺省，缺省的文件视为序号 0，
+// 因此旧版本产生的 app.20240101.log 与 app.20240101.1.log 一样参与压缩与清理。
+type pattern struct {
+	dir    string
+	tokens []token
+	re     *regexp.Regexp // 匹配本模式产生的文件名（含 .gz 与 .gz.tmp 后缀）
+	verbs  []byte         // re 中各捕获组对应的占位符
+	timed  bool           // 是否包含时间占位符
+}
+
+type token struct {
+	verb    byte // 0 表示字面量
+	literal string
+}
+
+func parsePattern(p string) (*pattern, error) {
+	dir, base := filepath.Split(p)
+	if strings.Contains(dir, "%") {
+		return nil, fmt.Errorf("directory part of pattern %q must not contain verbs", p)
+	}
+	if dir == "" {
+		dir = "."
+	}
+	if base == "" {
+		return nil, fmt.Errorf("pattern %q has no file name", p)
+	}
+
+	if !strings.Contains(base, "%N") {
+		ext := filepath.Ext(base)
+		if ext == "" || strings.Contains(ext, "%") {
+			base += ".%N"
+		} else {
+			base = strings.TrimSuffix(base, ext) + ".%N" + ext
+		}
+	}
+
+	pt := &pattern{dir: filepath.Clean(dir)}
+	var lit strings.Builder
+	var re strings.Builder
+	re.WriteString("^")
+
+	flush := func() {
+		if lit.Len() > 0 {
+			pt.tokens = append(pt.tokens, token{literal: lit.String()})
+			re.WriteString(regexp.QuoteMeta(lit.String()))
+			lit.Reset()
+		}
+	}
+
+	for i := 0; i < len(base); i++ {
+		c := base[i]
+		if c != '%' {
+			lit.WriteByte(c)
+			continue
+		}
+		if i+1 >= len(base) {
+			return nil, fmt.Errorf("pattern %q ends with a dangling %%", p)
+		}
+		i++
+		verb := base[i]
+		switch verb {
+		case '%':
+			lit.WriteByte('%')
+		case 'Y':
+			flush()
+			pt.tokens = append(pt.tokens, token{verb: verb})
+			pt.verbs = append(pt.verbs, verb)
+			pt.timed = true
+			re.WriteString(`(\d{4})`)
+		case 'm', 'd', 'H', 'M', 'S':
+			flush()
+			pt.tokens = append(pt.tokens, token{verb: verb})
+			pt.verbs = append(pt.verbs, verb)
+			pt.timed = true
+			re.WriteString(`(\d{2})`)
+		case 'N':
+			if s := lit.String(); strings.HasSuffix(s, ".") {
+				lit.Reset()
+				lit.WriteString(s[:len(s)-1])
+				flush()
+				pt.tokens = append(pt.tokens, token{literal: "."})
+				re.WriteString(`(?:\.(\d+))?`)
+			} else {
+				flush()
+				re.WriteString(`(\d+)`)
+			}
+			pt.tokens = append(pt.tokens, token{verb: verb})
+			pt.verbs = append(pt.verbs, verb)
+		default:
+			return nil, fmt.Errorf("pattern %q contains unknown verb %%%c", p, verb)
+		}
+	}
+	flush()
+	re.WriteString(`(\.gz(?:\.tmp)?)?$`)
+
+	compiled, err := regexp.Compile(re.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile pattern %q: %w", p, err)
+	}
+	pt.re = compiled
+	return pt, nil
+}
+
+// format 生成 t 时间段内第 seq 个文件的完整路径
+func (p *pattern) format(t time.Time, seq int) string {
+	var b strings.Builder
+	for _, tk := range p.tokens {
+		switch tk.verb {
+		case 0:
+			b.WriteString(tk.literal)
+		case 'N':
+			b.WriteString(strconv.Itoa(seq))
+		default:
+			b.WriteString(formatVerb(t, tk.verb))
+		}
+	}
+	return filepath.Join(p.dir, b.String())
+}
+
+// key 返回时间占位符格式化后的结果，文件名中时间部分相同的文件共享序号空间
+func (p *pattern) key(t time.Time) string {
+	var b strings.Builder
+	for _, verb := range p.verbs {
+		if verb != 'N' {
+			b.WriteString(formatVerb(t, verb))
+			b.WriteByte('|')
+		}
+	}
+	return b.String()
+}
+
+func formatVerb(t time.Time, verb byte) string {
+	switch verb {
+	case 'Y':
+		return fmt.Sprintf("%04d", t.Year())
+	case 'm':
+		return fmt.Sprintf("%02d", int(t.Month()))
+	case 'd':
+		return fmt.Sprintf("%02d", t.Day())
+	case 'H':
+		return fmt.Sprintf("%02d", t.Hour())
+	case 'M':
+		return fmt.Sprintf("%02d", t.Minute())
+	case 'S':
+		return fmt.Sprintf("%02d", t.Second())
+	}
+	return ""
+}
+
+// logFile 目录中一个由本模式产生的文件
+type logFile struct {
+	path       string
+	key        string
+	time       time.Time
+	seq        int
+	compressed bool
+	partial    bool // 未完成的 .gz.tmp
+	size       int64
+	modTime    time.Time
+}
+
+// match 解析文件名，不属于本模式时返回 false
+func (p *pattern) match(name string, loc *time.Location) (logFile, bool) {
+	m := p.re.FindStringSubmatch(name)
+	if m == nil {
+		return logFile{}, false
+	}
+
+	f := logFile{path: filepath.Join(p.dir, name)}
+	year, month, day, hour, minute, sec := 1, 1, 1, 0, 0, 0
+	var key strings.Builder
+	for i, verb := range p.verbs {
+		v, _ := strconv.Atoi(m[i+1]) // 缺省的序号为空串，视为 0
+		switch verb {
+		case 'Y':
+			year = v
+		case 'm':
+			month = v
+		case 'd':
+			day = v
+		case 'H':
+			hour = v
+		case 'M':
+			minute = v
+		case 'S':
+			sec = v
+		case 'N':
+			f.seq = v
+			continue
+		}
+		key.WriteString(m[i+1])
+		key.WriteByte('|')
+	}
+	f.key = key.String()
+	f.time = time.Date(year, time.Month(month), day, hour, minute, sec, 0, loc)
+
+	switch m[len(m)-1] {
+	case ".gz":
+		f.compressed = true
+	case ".gz.tmp":
+		f.partial = true
+	}
+	return f, true
+}
+
+// ValidatePattern 校验文件名模式，不创建任何文件
+func ValidatePattern(p string) error {
+	_, err := parsePattern(p)
+	return err
+}
@@ -0,0 +1,315 @@
+// Package rotate 提供按时间与大小轮转的日志文件写入器。
+//
+// 文件名由模式生成，例如 "logs/app.%Y%m%d.%N.log" 会依次产生
+// app.20240101.1.log、app.20240101.2.log（按大小轮转）、app.20240102.1.log（按时间轮转）。
+// 旧文件可在后台压缩为 .gz，并按数量、时长与总大小清理。
+package rotate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Writer 轮转文件写入器，可安全地并发使用
+type Writer struct {
+	pattern *pattern
+	opts    options
+
+	mu      sync.Mutex
+	file    *os.File
+	curPath string
+	size    int64
+	period  time.Time
+	key     string
+	seq     int
+	closed  bool
+
+	millCh   chan struct{}
+	millDone chan struct{}
+}
+
+// New 根据文件名模式创建写入器
+func New(filePattern string, opts ...Option) (*Writer, error) {
+	pt, err := parsePattern(filePattern)
+	if err != nil {
+		return nil, err
+	}
+
+	o := options{
+		clock:        Local,
+		rotationTime: 24 * time.Hour,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if err := os.MkdirAll(pt.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", pt.dir, err)
+	}
+
+	w := &Writer{
+		pattern:  pt,
+		opts:     o,
+		millCh:   make(chan struct{}, 1),
+		millDone: make(chan struct{}),
+	}
+
+	// 先确定当前文件，避免后台压缩处理到即将续写的文件
+	if err := w.openExisting(o.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	go w.millLoop()
+	// 启动时处理上次遗留的文件
+	w.signalMill()
+
+	return w, nil
+}
+
+// Write 实现 io.Writer，必要时先轮转再写入
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+
+	now := w.opts.clock.Now()
+
+	switch {
+	case w.opts.rotationTime > 0 && !w.periodStart(now).Equal(w.period):
+		if err := w.rotate(now); err != nil {
+			return 0, err
+		}
+	case w.opts.rotationSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.opts.rotationSize:
+		if err := w.rotate(now); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Sync 将当前文件刷入磁盘
+func (w *Writer) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+// Rotate 立即轮转到新文件
+func (w *Writer) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return os.ErrClosed
+	}
+	return w.rotate(w.opts.clock.Now())
+}
+
+// CurrentFile 返回当前写入的文件路径
+func (w *Writer) CurrentFile() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.curPath
+}
+
+// Close 关闭当前文件，并等待后台压缩与清理结束
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+
+	var err error
+	if w.file != nil {
+		err = w.file.Close()
+		w.file = nil
+	}
+	w.mu.Unlock()
+
+	close(w.millCh)
+	<-w.millDone
+	return err
+}
+
+// periodStart 返回 t 所在时间段的起点，按本地时间（而非 UTC）对齐
+func (w *Writer) periodStart(t time.Time) time.Time {
+	if w.opts.rotationTime <= 0 {
+		return time.Time{}
+	}
+	_, offset := t.Zone()
+	shift := time.Duration(offset) * time.Second
+	return t.Add(shift).Truncate(w.opts.rotationTime).Add(-shift)
+}
+
+// openExisting 创建时调用：续写当前时间段最新的文件，已满则新建
+func (w *Writer) openExisting(now time.Time) error {
+	period := w.periodStart(now)
+	key := w.pattern.key(period)
+
+	files, err := w.listFiles()
+	if err != nil {
+		return err
+	}
+
+	var latest *logFile
+	for i := range files {
+		f := &files[i]
+		if f.key != key {
+			continue
+		}
+		// 同一序号既有原文件又有压缩文件时，说明压缩已开始，不再续写
+		if latest == nil || f.seq > latest.seq || f.seq == latest.seq && (f.compressed || f.partial) {
+			latest = f
+		}
+	}
+
+	if latest != nil && !latest.compressed && !latest.partial &&
+		(w.opts.rotationSize <= 0 || latest.size < w.opts.rotationSize) {
+		return w.openFile(latest.path, period, key, latest.seq)
+	}
+
+	seq := 1
+	if latest != nil {
+		seq = latest.seq + 1
+	}
+	return w.openFile(w.pattern.format(period, seq), period, key, seq)
+}
+
+// rotate 关闭当前文件并切换到下一个文件
+func (w *Writer) rotate(now time.Time) error {
+	period := w.periodStart(now)
+	key := w.pattern.key(period)
+
+	seq := 1
+	files, err := w.listFiles()
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if f.key == key && f.seq >= seq {
+			seq = f.seq + 1
+		}
+	}
+
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			return fmt.Errorf("failed to close %s: %w", w.curPath, err)
+		}
+		w.file = nil
+	}
+
+	if err := w.openFile(w.pattern.format(period, seq), period, key, seq); err != nil {
+		return err
+	}
+
+	w.signalMill()
+	return nil
+}
+
+func (w *Writer) openFile(path string, period time.Time, key string, seq int) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	w.file = f
+	w.curPath = path
+	w.size = fi.Size()
+	w.period = period
+	w.key = key
+	w.seq = seq
+
+	if w.opts.linkName != "" {
+		if err := w.link(path); err != nil {
+			fmt.Fprintf(os.Stderr, "[rotate] %v\n", err)
+		}
+	}
+	return nil
+}
+
+// link 原子地将软链接指向 target
+func (w *Writer) link(target string) error {
+	linkDir := filepath.Dir(w.opts.linkName)
+	if err := os.MkdirAll(linkDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", linkDir, err)
+	}
+
+	dest := target
+	if rel, err := filepath.Rel(linkDir, target); err == nil {
+		dest = rel
+	}
+
+	tmp := w.opts.linkName + "_symlink"
+	_ = os.Remove(tmp)
+	if err := os.Symlink(dest, tmp); err != nil {
+		return fmt.Errorf("failed to create symlink: %w", err)
+	}
+	if err := os.Rename(tmp, w.opts.linkName); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to rename symlink: %w", err)
+	}
+	return nil
+}
+
+// listFiles 列出目录中由本模式产生的所有文件
+func (w *Writer) listFiles() ([]logFile, error) {
+	entries, err := os.ReadDir(w.pattern.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read directory %s: %w", w.pattern.dir, err)
+	}
+
+	loc := w.opts.clock.Now().Location()
+	files := make([]logFile, 0, len(entries))
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		f, ok := w.pattern.match(e.Name(), loc)
+		if !ok {
+			continue
+		}
+		if fi, err := e.Info(); err == nil {
+			f.size = fi.Size()
+			f.modTime = fi.ModTime()
+		}
+		files = append(files, f)
+	}
+
+	// 从旧到新排序
+	sort.Slice(files, func(i, j int) bool {
+		a, b := files[i], files[j]
+		if !w.pattern.timed && !a.modTime.Equal(b.modTime) {
+			return a.modTime.Before(b.modTime)
+		}
+		if !a.time.Equal(b.time) {
+			return a.time.Before(b.time)
+		}
+		return a.seq < b.seq
+	})
+	return files, nil
+}
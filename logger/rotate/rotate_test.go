@@ -0,0 +1,349 @@
+package rotate
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testClock 手动推进的时钟，每次调用 Now 前进 step
+type testClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)}
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := c.now
+	c.now = c.now.Add(c.step)
+	return t
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestWriter(t *testing.T, dir string, clock Clock, opts ...Option) *Writer {
+	t.Helper()
+	w, err := New(filepath.Join(dir, "app.%Y%m%d.%N.log"), append([]Option{WithClock(clock)}, opts...)...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = w.Close() })
+	return w
+}
+
+func writeString(t *testing.T, w *Writer, s string) {
+	t.Helper()
+	if _, err := w.Write([]byte(s)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+}
+
+// listNames 返回目录中的文件名，已排序
+func listNames(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// readFile 读取文件内容，.gz 文件先解压
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, gzipSuffix) {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("gzip %s: %v", path, err)
+		}
+		r = gz
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func equalNames(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+}
+
+func TestRotateBySize(t *testing.T) {
+	dir := t.TempDir()
+	w := newTestWriter(t, dir, newTestClock(), WithRotationSize(12))
+
+	writeString(t, w, "line1\n")
+	writeString(t, w, "line2\n")
+	writeString(t, w, "line3\n") // 超出 12 字节，轮转到序号 2
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	equalNames(t, listNames(t, dir), "app.20240101.1.log", "app.20240101.2.log")
+	if got := readFile(t, filepath.Join(dir, "app.20240101.1.log")); got != "line1\nline2\n" {
+		t.Errorf("first file = %q", got)
+	}
+	if got := readFile(t, filepath.Join(dir, "app.20240101.2.log")); got != "line3\n" {
+		t.Errorf("second file = %q", got)
+	}
+}
+
+func TestRotateByTime(t *testing.T) {
+	dir := t.TempDir()
+	clock := newTestClock()
+	w := newTestWriter(t, dir, clock, WithRotationSize(12))
+
+	writeString(t, w, "day1-a\n")
+	writeString(t, w, "day1-b\n")
+	clock.Advance(24 * time.Hour)
+	writeString(t, w, "day2\n") // 新的时间段，序号从 1 开始
+
+	if got, want := w.CurrentFile(), filepath.Join(dir, "app.20240102.1.log"); got != want {
+		t.Errorf("CurrentFile = %s, want %s", got, want)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	equalNames(t, listNames(t, dir), "app.20240101.1.log", "app.20240101.2.log", "app.20240102.1.log")
+}
+
+func TestReopenContinuesLatestFile(t *testing.T) {
+	dir := t.TempDir()
+	clock := newTestClock()
+
+	w := newTestWriter(t, dir, clock, WithRotationSize(12))
+	writeString(t, w, "a\n")
+	_ = w.Close()
+
+	// 未写满的文件续写
+	w = newTestWriter(t, dir, clock, WithRotationSize(12))
+	writeString(t, w, "b\n")
+	if got, want := w.CurrentFile(), filepath.Join(dir, "app.20240101.1.log"); got != want {
+		t.Errorf("CurrentFile = %s, want %s", got, want)
+	}
+	writeString(t, w, "0123456789ab\n")
+	_ = w.Close()
+
+	// 已写满的文件不再续写，序号递增
+	w = newTestWriter(t, dir, clock, WithRotationSize(12))
+	if got, want := w.CurrentFile(), filepath.Join(dir, "app.20240101.3.log"); got != want {
+		t.Errorf("CurrentFile = %s, want %s", got, want)
+	}
+}
+
+func TestMaxFiles(t *testing.T) {
+	dir := t.TempDir()
+	w := newTestWriter(t, dir, newTestClock(), WithMaxFiles(3))
+
+	for i := 0; i < 5; i++ {
+		writeString(t, w, fmt.Sprintf("file %d\n", i+1))
+		if i < 4 {
+			if err := w.Rotate(); err != nil {
+				t.Fatalf("Rotate: %v", err)
+			}
+		}
+	}
+	_ = w.Close()
+
+	equalNames(t, listNames(t, dir), "app.20240101.3.log", "app.20240101.4.log", "app.20240101.5.log")
+}
+
+// writeAt 写入后将当前文件的修改时间设为时钟的当前时间
+func writeAt(t *testing.T, w *Writer, clock *testClock, s string) {
+	t.Helper()
+	writeString(t, w, s)
+	now := clock.Now()
+	if err := os.Chtimes(w.CurrentFile(), now, now); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	clock := newTestClock()
+	w := newTestWriter(t, dir, clock, WithMaxAge(48*time.Hour))
+
+	for i := 0; i < 4; i++ {
+		writeAt(t, w, clock, "entry\n")
+		clock.Advance(24 * time.Hour)
+	}
+	writeAt(t, w, clock, "entry\n")
+	_ = w.Close()
+
+	// 当前时间为 01-05 10:00，最后写入早于 01-03 10:00 的文件被清理
+	equalNames(t, listNames(t, dir), "app.20240103.1.log", "app.20240104.1.log", "app.20240105.1.log")
+}
+
+func TestMaxAgeUsesLastWrite(t *testing.T) {
+	dir := t.TempDir()
+	clock := newTestClock()
+	w := newTestWriter(t, dir, clock, WithMaxAge(24*time.Hour))
+
+	// 01-01 的文件一直写到 23:30
+	writeAt(t, w, clock, "morning\n")
+	clock.Advance(13*time.Hour + 30*time.Minute)
+	writeAt(t, w, clock, "night\n")
+
+	// 01-02 12:00 距时间段起点已超过一天，但距最后写入不到一天
+	clock.Advance(12*time.Hour + 30*time.Minute)
+	writeAt(t, w, clock, "next day\n")
+	if err := w.mill(); err != nil {
+		t.Fatalf("mill: %v", err)
+	}
+	equalNames(t, listNames(t, dir), "app.20240101.1.log", "app.20240102.1.log")
+
+	// 01-03 00:00 距最后写入超过一天
+	clock.Advance(12 * time.Hour)
+	if err := w.mill(); err != nil {
+		t.Fatalf("mill: %v", err)
+	}
+	equalNames(t, listNames(t, dir), "app.20240102.1.log")
+}
+
+func TestCompress(t *testing.T) {
+	dir := t.TempDir()
+	w := newTestWriter(t, dir, newTestClock(), WithCompress(true))
+
+	writeString(t, w, "old\n")
+	if err := w.Rotate(); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	writeString(t, w, "new\n")
+	_ = w.Close()
+
+	equalNames(t, listNames(t, dir), "app.20240101.1.log.gz", "app.20240101.2.log")
+	if got := readFile(t, filepath.Join(dir, "app.20240101.1.log.gz")); got != "old\n" {
+		t.Errorf("compressed file = %q", got)
+	}
+}
+
+func TestLegacyFileNames(t *testing.T) {
+	dir := t.TempDir()
+	// 旧版本产生的不带序号的文件
+	for _, name := range []string{"app.20231230.log", "app.20231231.log"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("legacy\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := newTestWriter(t, dir, newTestClock(), WithCompress(true), WithMaxFiles(2))
+	writeString(t, w, "entry\n")
+	_ = w.Close()
+
+	equalNames(t, listNames(t, dir), "app.20231231.log.gz", "app.20240101.1.log")
+}
+
+func TestCrashRecovery(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.20240101.1.log":        "first\n",
+		"app.20240101.1.log.gz.tmp": "partial", // 压缩中途崩溃，原文件仍在
+		"app.20240101.2.log":        "second\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 压缩完成但原文件未删除
+	if err := compressFile(filepath.Join(dir, "app.20240101.2.log")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "app.20240101.2.log"), []byte("second\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := newTestWriter(t, dir, newTestClock())
+	// 序号 2 已开始压缩，不能续写
+	if got, want := w.CurrentFile(), filepath.Join(dir, "app.20240101.3.log"); got != want {
+		t.Errorf("CurrentFile = %s, want %s", got, want)
+	}
+	_ = w.Close()
+
+	equalNames(t, listNames(t, dir), "app.20240101.1.log", "app.20240101.2.log.gz", "app.20240101.3.log")
+	if got := readFile(t, filepath.Join(dir, "app.20240101.2.log.gz")); got != "second\n" {
+		t.Errorf("compressed file = %q", got)
+	}
+}
+
+func TestMillSkipsNewerFiles(t *testing.T) {
+	dir := t.TempDir()
+	w := newTestWriter(t, dir, newTestClock(), WithCompress(true), WithMaxFiles(1))
+	writeString(t, w, "current\n")
+
+	// 模拟列出文件时尚未存在、随后由轮转创建的文件
+	newer := filepath.Join(dir, "app.20240101.2.log")
+	if err := os.WriteFile(newer, []byte("newer\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.mill(); err != nil {
+		t.Fatalf("mill: %v", err)
+	}
+
+	equalNames(t, listNames(t, dir), "app.20240101.1.log", "app.20240101.2.log")
+}
+
+// TestNoLossUnderRotation 高频轮转并压缩时，写入的每一行都应保留在磁盘上
+func TestNoLossUnderRotation(t *testing.T) {
+	dir := t.TempDir()
+	clock := newTestClock()
+	clock.step = time.Millisecond
+	w := newTestWriter(t, dir, clock,
+		WithRotationTime(time.Second),
+		WithRotationSize(2000),
+		WithCompress(true),
+	)
+
+	const n = 20000
+	for i := 0; i < n; i++ {
+		writeString(t, w, fmt.Sprintf("line %d\n", i))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	var lines int
+	for _, name := range listNames(t, dir) {
+		sc := bufio.NewScanner(strings.NewReader(readFile(t, filepath.Join(dir, name))))
+		for sc.Scan() {
+			lines++
+		}
+	}
+	if lines != n {
+		t.Fatalf("lines on disk = %d, want %d", lines, n)
+	}
+}
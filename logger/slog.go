@@ -0,0 +1,260 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// slogHandler 将 slog 记录写入 zap core
+type slogHandler struct {
+	core    zapcore.Core
+	name    string
+	stack   zapcore.LevelEnabler // 记录堆栈的级别，与 logger 的 StacktraceLevel 一致
+	pending []string             // WithGroup 打开但尚无字段的组，有字段时才写出，与 slog 忽略空组的约定一致
+}
+
+// SlogHandler 返回写入该 logger 的 slog.Handler。
+// slog 的组映射为嵌套对象，调用位置取自记录的 PC，达到 StacktraceLevel 的记录附带堆栈，
+// ctx 中的附加字段与 trace 字段会一并写出
+func (l *Logger) SlogHandler() slog.Handler {
+	return &slogHandler{
+		core:  l.Logger.Core(),
+		name:  l.Logger.Name(),
+		stack: l.stackLevel,
+	}
+}
+
+// Slog 返回写入该 logger 的 *slog.Logger
+func (l *Logger) Slog() *slog.Logger {
+	return slog.New(l.SlogHandler())
+}
+
+// slogLevel 将 slog 级别映射为 zap 级别，高于 Error 的级别仍记为 Error，不会触发 panic 或退出
+func slogLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return zapcore.DebugLevel
+	case level < slog.LevelWarn:
+		return zapcore.InfoLevel
+	case level < slog.LevelError:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.core.Enabled(slogLevel(level))
+}
+
+func (h *slogHandler) Handle(ctx context.Context, record slog.Record) error {
+	ent := zapcore.Entry{
+		Level:      slogLevel(record.Level),
+		Time:       record.Time,
+		LoggerName: h.name,
+		Message:    record.Message,
+	}
+	if record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		ent.Caller = zapcore.NewEntryCaller(record.PC, frame.File, frame.Line, true)
+		ent.Caller.Function = frame.Function
+	}
+
+	ce := h.core.Check(ent, nil)
+	if ce == nil {
+		return nil
+	}
+	if h.stack.Enabled(ent.Level) {
+		ce.Stack = slogStack(record.PC)
+	}
+
+	ctxFields := contextFields(ctx)
+	fields := make([]zapcore.Field, 0, len(ctxFields)+len(h.pending)+record.NumAttrs())
+	fields = append(fields, ctxFields...)
+
+	attrs := make([]zapcore.Field, 0, record.NumAttrs())
+	record.Attrs(func(a slog.Attr) bool {
+		attrs = appendSlogAttr(attrs, a)
+		return true
+	})
+	if len(attrs) > 0 {
+		for _, g := range h.pending {
+			fields = append(fields, zap.Namespace(g))
+		}
+		fields = append(fields, attrs...)
+	}
+
+	ce.Write(fields...)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make([]zapcore.Field, 0, len(h.pending)+len(attrs))
+	for _, g := range h.pending {
+		fields = append(fields, zap.Namespace(g))
+	}
+	n := len(fields)
+	for _, a := range attrs {
+		fields = appendSlogAttr(fields, a)
+	}
+	if len(fields) == n {
+		return h
+	}
+	return &slogHandler{
+		core:  h.core.With(fields),
+		name:  h.name,
+		stack: h.stack,
+	}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	pending := make([]string, 0, len(h.pending)+1)
+	pending = append(pending, h.pending...)
+	return &slogHandler{
+		core:    h.core,
+		name:    h.name,
+		stack:   h.stack,
+		pending: append(pending, name),
+	}
+}
+
+// slogStack 返回从 pc 所在帧开始的堆栈，格式与 zap 一致。
+// pc 不在当前协程的调用栈中时（记录被转交给其他协程处理）从 Handle 的调用方开始
+func slogStack(pc uintptr) string {
+	pcs := make([]uintptr, 64)
+	for {
+		// 跳过 runtime.Callers、slogStack 与 Handle
+		n := runtime.Callers(3, pcs)
+		if n < len(pcs) {
+			pcs = pcs[:n]
+			break
+		}
+		pcs = make([]uintptr, 2*len(pcs))
+	}
+	for i, p := range pcs {
+		if p == pc {
+			pcs = pcs[i:]
+			break
+		}
+	}
+
+	// 与 zap 一样略去最后的 runtime.main 或 runtime.goexit 帧
+	var b strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for frame, more := frames.Next(); more; frame, more = frames.Next() {
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(frame.Function)
+		b.WriteString("\n\t")
+		b.WriteString(frame.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(frame.Line))
+	}
+	return b.String()
+}
+
+// appendSlogAttr 将 slog 属性转为 zap 字段，空属性被忽略，无名组的属性直接展开
+func appendSlogAttr(fields []zapcore.Field, a slog.Attr) []zapcore.Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return append(fields, zap.String(a.Key, a.Value.String()))
+	case slog.KindInt64:
+		return append(fields, zap.Int64(a.Key, a.Value.Int64()))
+	case slog.KindUint64:
+		return append(fields, zap.Uint64(a.Key, a.Value.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, zap.Float64(a.Key, a.Value.Float64()))
+	case slog.KindBool:
+		return append(fields, zap.Bool(a.Key, a.Value.Bool()))
+	case slog.KindDuration:
+		return append(fields, zap.Duration(a.Key, a.Value.Duration()))
+	case slog.KindTime:
+		return append(fields, zap.Time(a.Key, a.Value.Time()))
+	case slog.KindGroup:
+		group := a.Value.Group()
+		if len(group) == 0 {
+			return fields
+		}
+		if a.Key == "" {
+			for _, ga := range group {
+				fields = appendSlogAttr(fields, ga)
+			}
+			return fields
+		}
+		return append(fields, zap.Object(a.Key, slogGroup(group)))
+	default:
+		return append(fields, zap.Any(a.Key, a.Value.Any()))
+	}
+}
+
+// slogGroup 将 slog 组编码为嵌套对象
+type slogGroup []slog.Attr
+
+func (g slogGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	var fields []zapcore.Field
+	for _, a := range g {
+		fields = appendSlogAttr(fields, a)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return nil
+}
+
+// setSlogDefault 将 slog 默认 logger 指向 logger，传入 nil 时恢复原来的默认 logger。
+// slog.SetDefault 会同时接管标准库 log 的输出，状态记录在 logRedirect 中，恢复时一并还原
+func setSlogDefault(logger *Logger) {
+	logRedirect.mu.Lock()
+	defer logRedirect.mu.Unlock()
+
+	if logger == nil {
+		resetSlogDefaultLocked()
+		return
+	}
+
+	saveLogLocked()
+	if logRedirect.slogOwner == nil {
+		logRedirect.slogPrev = slog.Default()
+	}
+	logRedirect.slogOwner = logger
+	slog.SetDefault(logger.Slog())
+	// 标准库 log 已重定向时保持重定向
+	applyLogLocked()
+}
+
+// restoreSlogDefault slog 默认 logger 由 l 设置时恢复原来的默认 logger
+func restoreSlogDefault(l *Logger) {
+	logRedirect.mu.Lock()
+	defer logRedirect.mu.Unlock()
+
+	if logRedirect.slogOwner == l {
+		resetSlogDefaultLocked()
+	}
+}
+
+// resetSlogDefaultLocked 恢复原来的 slog 默认 logger，调用方需持有锁
+func resetSlogDefaultLocked() {
+	if logRedirect.slogOwner == nil {
+		return
+	}
+	// 恢复为内置的默认 logger 时 slog.SetDefault 不会改动标准库 log，由 applyLogLocked 设置
+	slog.SetDefault(logRedirect.slogPrev)
+	logRedirect.slogOwner = nil
+	logRedirect.slogPrev = nil
+	applyLogLocked()
+}
@@ -0,0 +1,119 @@
+package logger_test
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/constellation39/framework/logger"
+	"github.com/constellation39/framework/logger/loggertest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSlogStacktrace(t *testing.T) {
+	r := loggertest.New(t)
+	sl := r.Slog()
+
+	sl.Warn("slow")
+	sl.Error("failed")
+	sl.Log(context.Background(), slog.LevelError+4, "fatal-ish") // 高于 Error 仍记为 Error
+
+	entries := r.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	if entries[0].Stack != "" {
+		t.Errorf("warn entry has a stack:\n%s", entries[0].Stack)
+	}
+	for _, e := range entries[1:] {
+		if e.Level != zapcore.ErrorLevel {
+			t.Errorf("%s: level = %s, want error", e.Message, e.Level)
+		}
+		// 堆栈从调用 slog 之处开始，不含 slog 与本日志包的帧
+		first, _, _ := strings.Cut(e.Stack, "\n")
+		if first != "github.com/constellation39/framework/logger_test.TestSlogStacktrace" {
+			t.Errorf("%s: stack starts at %q:\n%s", e.Message, first, e.Stack)
+		}
+		if strings.Contains(e.Stack, "runtime.goexit") {
+			t.Errorf("%s: stack includes runtime.goexit", e.Message)
+		}
+	}
+}
+
+func TestSlogStacktraceLevel(t *testing.T) {
+	r := loggertest.New(t, logger.WithStacktrace(true, "warn", 0))
+	r.Slog().Warn("slow")
+	r.Module("db").Slog().WithGroup("g").With("k", "v").Warn("module")
+
+	for _, e := range r.Entries() {
+		if !strings.Contains(e.Stack, "logger_test.TestSlogStacktraceLevel") {
+			t.Errorf("%s: stack = %q", e.Message, e.Stack)
+		}
+	}
+
+	// 关闭堆栈跟踪后不再记录
+	r = loggertest.New(t, logger.WithStacktrace(false, "", 0))
+	r.Slog().Error("failed")
+	if e := r.Entries(); len(e) != 1 || e[0].Stack != "" {
+		t.Errorf("entries = %+v, want one entry without stack", e)
+	}
+}
+
+func TestSlogGroups(t *testing.T) {
+	r := loggertest.New(t)
+	sl := r.Slog()
+
+	sl.WithGroup("req").With("id", 7).WithGroup("body").Info("nested",
+		slog.Group("user", "name", "alice"),
+		slog.Group("", "inline", true), // 无名组直接展开
+		slog.Group("empty"),            // 空组被忽略
+		"n", 2,
+	)
+	sl.WithGroup("unused").Info("bare") // 没有字段的组不写出
+
+	want := map[string]map[string]interface{}{
+		"nested": {
+			"req": map[string]interface{}{
+				"id": int64(7),
+				"body": map[string]interface{}{
+					"user":   map[string]interface{}{"name": "alice"},
+					"inline": true,
+					"n":      int64(2),
+				},
+			},
+		},
+		"bare": {},
+	}
+	for _, e := range r.Entries() {
+		if got := e.ContextMap(); !reflect.DeepEqual(got, want[e.Message]) {
+			t.Errorf("%s: fields = %#v\nwant %#v", e.Message, got, want[e.Message])
+		}
+	}
+}
+
+func TestSlogCallerAndContext(t *testing.T) {
+	r := loggertest.New(t, logger.WithCallerFunction(true))
+	ctx := logger.WithFields(context.Background(), zap.String("request_id", "r1"))
+
+	_, file, line, _ := runtime.Caller(0)
+	r.Module("api").Slog().InfoContext(ctx, "handled")
+
+	entries := r.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	if !e.Caller.Defined || e.Caller.File != file || e.Caller.Line != line+1 {
+		t.Errorf("caller = %s, want %s:%d", e.Caller, file, line+1)
+	}
+	if e.Caller.Function != "github.com/constellation39/framework/logger_test.TestSlogCallerAndContext" {
+		t.Errorf("caller function = %q", e.Caller.Function)
+	}
+	if e.LoggerName != "api" || e.ContextMap()["request_id"] != "r1" {
+		t.Errorf("logger = %q, fields = %v", e.LoggerName, e.ContextMap())
+	}
+}
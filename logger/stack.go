@@ -0,0 +1,183 @@
+package logger
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 堆栈格式
+const (
+	StackText       = "text"       // zap 原始文本，按 MaxStackFrames 截断
+	StackStructured = "structured" // 过滤帧并保留首尾，JSON 编码为 {func,file,line} 数组，控制台为缩进文本
+)
+
+const (
+	defaultStackHeadFrames = 8
+	defaultStackTailFrames = 2
+)
+
+// defaultStackFilter 默认丢弃的帧所属的包：运行时、zap 与本日志包
+var defaultStackFilter = []string{"runtime", "go.uber.org/zap", reflect.TypeOf(Logger{}).PkgPath()}
+
+// stackFrame 堆栈中的一帧
+type stackFrame struct {
+	function string
+	file     string
+	line     int
+}
+
+func (f stackFrame) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("func", f.function)
+	enc.AddString("file", f.file)
+	enc.AddInt("line", f.line)
+	return nil
+}
+
+// stackTrace 过滤后的堆栈，omitted 个帧在 head 帧之后被省略
+type stackTrace struct {
+	frames  []stackFrame
+	head    int
+	omitted int
+}
+
+func (s stackTrace) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	omitted := zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddInt("omitted", s.omitted)
+		return nil
+	})
+	for i, f := range s.frames {
+		if i == s.head && s.omitted > 0 {
+			_ = enc.AppendObject(omitted)
+		}
+		_ = enc.AppendObject(f)
+	}
+	// tail 为 0 时省略标记位于末尾
+	if s.head == len(s.frames) && s.omitted > 0 {
+		_ = enc.AppendObject(omitted)
+	}
+	return nil
+}
+
+// String 格式化为与 zap 一致的缩进文本
+func (s stackTrace) String() string {
+	var b strings.Builder
+	for i, f := range s.frames {
+		if i == s.head && s.omitted > 0 {
+			b.WriteString("\t... " + strconv.Itoa(s.omitted) + " frames omitted ...\n")
+		}
+		b.WriteString(f.function)
+		b.WriteString("\n\t")
+		b.WriteString(f.file)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(f.line))
+		b.WriteByte('\n')
+	}
+	if s.head == len(s.frames) && s.omitted > 0 {
+		b.WriteString("\t... " + strconv.Itoa(s.omitted) + " frames omitted ...\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// parseStack 解析 zap 生成的堆栈文本，每帧为函数名一行、制表符缩进的 "文件:行号" 一行
+func parseStack(stack string) []stackFrame {
+	lines := strings.Split(stack, "\n")
+	frames := make([]stackFrame, 0, len(lines)/2)
+	for i := 0; i+1 < len(lines); i += 2 {
+		location := strings.TrimSpace(lines[i+1])
+		frame := stackFrame{function: lines[i], file: location}
+		if j := strings.LastIndexByte(location, ':'); j >= 0 {
+			if line, err := strconv.Atoi(location[j+1:]); err == nil {
+				frame.file, frame.line = location[:j], line
+			}
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+// structuredStackCore 以 StackStructured 格式输出堆栈
+type structuredStackCore struct {
+	zapcore.Core
+	key    string // 堆栈字段名
+	array  bool   // 输出为帧数组，否则为文本
+	filter []string
+	head   int
+	tail   int
+}
+
+func newStructuredStackCore(cfg *Config, out *OutputConfig, core zapcore.Core) zapcore.Core {
+	filter := cfg.StackFilter
+	if filter == nil {
+		filter = defaultStackFilter
+	}
+	return &structuredStackCore{
+		Core:   core,
+		key:    firstNonEmpty(out.Encoder.StacktraceKey, "stacktrace"),
+		array:  strings.ToLower(outputEncoding(cfg, out)) == "json",
+		filter: filter,
+		head:   cfg.StackHeadFrames,
+		tail:   cfg.StackTailFrames,
+	}
+}
+
+func (c *structuredStackCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	return &clone
+}
+
+func (c *structuredStackCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *structuredStackCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Stack == "" {
+		return c.Core.Write(ent, fields)
+	}
+
+	trace := c.trace(ent.Stack)
+	if c.array {
+		ent.Stack = ""
+		fields = append(fields[:len(fields):len(fields)], zap.Array(c.key, trace))
+	} else {
+		ent.Stack = trace.String()
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// trace 解析并过滤堆栈，超出 head+tail 帧时省略中间部分
+func (c *structuredStackCore) trace(stack string) stackTrace {
+	all := parseStack(stack)
+	frames := all[:0]
+	for _, f := range all {
+		if !c.filtered(f.function) {
+			frames = append(frames, f)
+		}
+	}
+
+	trace := stackTrace{frames: frames}
+	if (c.head > 0 || c.tail > 0) && len(frames) > c.head+c.tail {
+		trace.omitted = len(frames) - c.head - c.tail
+		trace.frames = append(frames[:c.head:c.head], frames[len(frames)-c.tail:]...)
+		trace.head = c.head
+	}
+	return trace
+}
+
+// filtered 判断函数是否属于被过滤的包，按包路径边界匹配
+func (c *structuredStackCore) filtered(function string) bool {
+	pkg := funcPackage(function)
+	for _, prefix := range c.filter {
+		if pkg == prefix || strings.HasPrefix(pkg, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
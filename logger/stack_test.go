@@ -0,0 +1,241 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// rawStack 按 zap 的格式生成堆栈文本
+func rawStack(functions ...string) string {
+	lines := make([]string, 0, 2*len(functions))
+	for i, fn := range functions {
+		lines = append(lines, fn, fmt.Sprintf("\t/src/f%d.go:%d", i, i+1))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func traceFunctions(s stackTrace) []string {
+	functions := make([]string, len(s.frames))
+	for i, f := range s.frames {
+		functions[i] = f.function
+	}
+	return functions
+}
+
+func TestParseStack(t *testing.T) {
+	frames := parseStack("main.main\n\t/src/app/main.go:12\nmain.run\n\t/src/app/run.go")
+	want := []stackFrame{
+		{function: "main.main", file: "/src/app/main.go", line: 12},
+		{function: "main.run", file: "/src/app/run.go"},
+	}
+	if !reflect.DeepEqual(frames, want) {
+		t.Errorf("frames = %+v, want %+v", frames, want)
+	}
+}
+
+func TestStackFilterPackages(t *testing.T) {
+	c := &structuredStackCore{filter: append(defaultStackFilter, "example.com/app/internal")}
+	trace := c.trace(rawStack(
+		"runtime/debug.Stack",
+		"go.uber.org/zap/zapcore.(*CheckedEntry).Write",
+		"go.uber.org/zap.(*Logger).Error",
+		"github.com/constellation39/framework/logger.(*Logger).ErrorCtx",
+		"github.com/constellation39/framework/logger/httplog.Middleware.func1",
+		"example.com/app/internal/db.(*Pool).Query",
+		"example.com/app/internalize.Run", // 只匹配包路径边界
+		"runtimeutil.Do",
+		"main.main",
+		"runtime.main",
+		"runtime.goexit",
+	))
+
+	want := []string{"example.com/app/internalize.Run", "runtimeutil.Do", "main.main"}
+	if got := traceFunctions(trace); !reflect.DeepEqual(got, want) {
+		t.Errorf("frames = %q, want %q", got, want)
+	}
+	if trace.omitted != 0 {
+		t.Errorf("omitted = %d, want 0", trace.omitted)
+	}
+}
+
+func TestStackElision(t *testing.T) {
+	functions := make([]string, 12)
+	for i := range functions {
+		functions[i] = fmt.Sprintf("main.f%d", i)
+	}
+	stack := rawStack(functions...)
+
+	tests := []struct {
+		name        string
+		head, tail  int
+		wantFrames  []string
+		wantOmitted int
+		wantText    string
+	}{
+		{
+			name: "head and tail", head: 2, tail: 1,
+			wantFrames:  []string{"main.f0", "main.f1", "main.f11"},
+			wantOmitted: 9,
+			wantText:    "main.f0\n\t/src/f0.go:1\nmain.f1\n\t/src/f1.go:2\n\t... 9 frames omitted ...\nmain.f11\n\t/src/f11.go:12",
+		},
+		{
+			name: "head only", head: 1,
+			wantFrames:  []string{"main.f0"},
+			wantOmitted: 11,
+			wantText:    "main.f0\n\t/src/f0.go:1\n\t... 11 frames omitted ...",
+		},
+		{
+			name: "tail only", tail: 1,
+			wantFrames:  []string{"main.f11"},
+			wantOmitted: 11,
+			wantText:    "\t... 11 frames omitted ...\nmain.f11\n\t/src/f11.go:12",
+		},
+		{
+			name: "fits", head: 10, tail: 2,
+			wantFrames: functions,
+		},
+		{
+			name:       "disabled",
+			wantFrames: functions,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &structuredStackCore{head: tt.head, tail: tt.tail}
+			trace := c.trace(stack)
+			if got := traceFunctions(trace); !reflect.DeepEqual(got, tt.wantFrames) {
+				t.Errorf("frames = %q, want %q", got, tt.wantFrames)
+			}
+			if trace.omitted != tt.wantOmitted {
+				t.Errorf("omitted = %d, want %d", trace.omitted, tt.wantOmitted)
+			}
+			if tt.wantText != "" && trace.String() != tt.wantText {
+				t.Errorf("text = %q\nwant %q", trace.String(), tt.wantText)
+			}
+		})
+	}
+}
+
+// stackOutput 写入 dir 的 JSON 文件输出
+func stackOutput(dir, stackFormat string) OutputConfig {
+	return OutputConfig{
+		Type:    OutputFile,
+		File:    FileOutputConfig{Dir: dir, Filename: "app"},
+		Encoder: EncoderConfig{StackFormat: stackFormat},
+	}
+}
+
+// loggedStack 记录一条 error 日志，返回各目录中该日志的 stacktrace 字段
+func loggedStack(t *testing.T, configFormat string, outputFormats ...string) []interface{} {
+	t.Helper()
+	dirs := make([]string, len(outputFormats))
+	outputs := make([]OutputConfig, len(outputFormats))
+	for i, format := range outputFormats {
+		dirs[i] = t.TempDir()
+		outputs[i] = stackOutput(dirs[i], format)
+	}
+
+	l, err := New(
+		WithFile(false, "", ""),
+		WithConsole(false, false),
+		WithOutputs(outputs...),
+		// 测试函数位于本包，不使用默认过滤
+		WithStackFormat(configFormat, 1, 0, "runtime", "go.uber.org/zap"),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	l.Error("failed")
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	stacks := make([]interface{}, len(dirs))
+	for i, dir := range dirs {
+		lines := readJSONLines(t, dir)
+		if len(lines) != 1 {
+			t.Fatalf("%s: got %d lines, want 1", dir, len(lines))
+		}
+		stacks[i] = lines[0]["stacktrace"]
+	}
+	return stacks
+}
+
+func TestStackStructuredJSON(t *testing.T) {
+	stack := loggedStack(t, StackStructured, "")[0]
+
+	frames, ok := stack.([]interface{})
+	if !ok || len(frames) != 2 {
+		t.Fatalf("stacktrace = %#v, want a frame and the omitted marker", stack)
+	}
+	frame, _ := frames[0].(map[string]interface{})
+	if frame["func"] != "github.com/constellation39/framework/logger.loggedStack" ||
+		!strings.HasSuffix(frame["file"].(string), "stack_test.go") || frame["line"].(float64) <= 0 {
+		t.Errorf("first frame = %v", frame)
+	}
+	if len(frame) != 3 {
+		t.Errorf("frame keys = %v, want func, file and line", frame)
+	}
+	// TestStackStructuredJSON 与 testing.tRunner 被省略
+	if marker := frames[1]; !reflect.DeepEqual(marker, map[string]interface{}{"omitted": float64(2)}) {
+		t.Errorf("marker = %v, want {omitted: 2}", marker)
+	}
+}
+
+func TestStackFormatPerOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		outputs []string
+		want    []bool // 各输出是否为帧数组
+	}{
+		{"output overrides text", StackText, []string{StackStructured, ""}, []bool{true, false}},
+		{"output overrides structured", StackStructured, []string{StackText, ""}, []bool{false, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, stack := range loggedStack(t, tt.config, tt.outputs...) {
+				_, isArray := stack.([]interface{})
+				if isArray != tt.want[i] {
+					t.Errorf("outputs[%d] stacktrace = %#v, want array %v", i, stack, tt.want[i])
+				}
+				if text, ok := stack.(string); ok && !strings.Contains(text, "testing.tRunner") {
+					t.Errorf("outputs[%d] text stack was elided: %q", i, text)
+				}
+			}
+		})
+	}
+}
+
+func TestStackStructuredConsole(t *testing.T) {
+	dir := t.TempDir()
+	out := stackOutput(dir, StackStructured)
+	out.Encoding = "console"
+	l, err := New(
+		WithFile(false, "", ""),
+		WithConsole(false, false),
+		WithOutputs(out),
+		WithStackFormat(StackStructured, 1, 0, "runtime", "go.uber.org/zap"),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	l.Error("failed")
+	_ = l.Close()
+
+	data, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	if !strings.Contains(text, "logger.TestStackStructuredConsole\n") {
+		t.Errorf("stack missing the caller frame:\n%s", text)
+	}
+	if !strings.Contains(text, "\t... 1 frames omitted ...") || strings.Contains(text, "testing.tRunner") {
+		t.Errorf("stack not filtered and elided:\n%s", text)
+	}
+}
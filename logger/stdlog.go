@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// stdLogCallerSkip 从 stdLogWriter.Write 到 log.Printf 调用方之间的栈帧数
+const stdLogCallerSkip = 3
+
+// maxStdLogComponent "[component] message" 前缀中组件名的最大长度
+const maxStdLogComponent = 64
+
+// logRedirect 标准库 log 与 slog 默认 logger 的接管状态。
+// RedirectStdLog 与 slog.SetDefault 都会接管标准库 log 的输出，两者共用接管前的原始状态，
+// 任一方恢复时按另一方是否仍在接管决定 log 的去向，全部恢复后才还原原始输出
+var logRedirect struct {
+	mu sync.Mutex
+
+	saved  bool // 是否已记录原始状态
+	out    io.Writer
+	flags  int
+	prefix string
+
+	stdOwner  *Logger   // RedirectStdLog 的 logger
+	stdWriter io.Writer // 重定向使用的 stdLogWriter
+
+	slogOwner *Logger      // 设为 slog 默认 logger 的 logger
+	slogPrev  *slog.Logger // 之前的 slog 默认 logger
+}
+
+// saveLogLocked 首次接管前记录标准库 log 的原始状态，调用方需持有锁
+func saveLogLocked() {
+	if logRedirect.saved {
+		return
+	}
+	logRedirect.out = log.Writer()
+	logRedirect.flags = log.Flags()
+	logRedirect.prefix = log.Prefix()
+	logRedirect.saved = true
+}
+
+// applyLogLocked 按当前的接管方设置标准库 log 的输出：RedirectStdLog 优先，其次 slog，均未接管时还原原始状态。调用方需持有锁
+func applyLogLocked() {
+	if !logRedirect.saved {
+		return
+	}
+	switch {
+	case logRedirect.stdOwner != nil:
+		// 时间与调用位置由本日志记录
+		log.SetFlags(0)
+		log.SetPrefix("")
+		log.SetOutput(logRedirect.stdWriter)
+	case logRedirect.slogOwner != nil:
+		// slog.SetDefault 按当前的 log 标志接管输出
+		log.SetFlags(logRedirect.flags)
+		log.SetPrefix(logRedirect.prefix)
+		slog.SetDefault(slog.Default())
+	default:
+		log.SetOutput(logRedirect.out)
+		log.SetFlags(logRedirect.flags)
+		log.SetPrefix(logRedirect.prefix)
+		logRedirect.out = nil
+		logRedirect.saved = false
+	}
+}
+
+// RedirectStdLog 将标准库 log 的输出重定向到该 logger，级别为 Config.StdLogLevel。
+// "[component] message" 形式的前缀会作为 logger 名；Close 时自动恢复
+func (l *Logger) RedirectStdLog() error {
+	cfg := l.sinks.current().config
+	level, err := parseLevel(firstNonEmpty(cfg.StdLogLevel, "info"))
+	if err != nil {
+		return fmt.Errorf("invalid std log level %s: %w", cfg.StdLogLevel, err)
+	}
+
+	w := &stdLogWriter{
+		logger: l.Logger.WithOptions(zap.AddCallerSkip(stdLogCallerSkip)),
+		level:  level,
+	}
+
+	logRedirect.mu.Lock()
+	defer logRedirect.mu.Unlock()
+
+	saveLogLocked()
+	logRedirect.stdOwner = l
+	logRedirect.stdWriter = w
+	applyLogLocked()
+	return nil
+}
+
+// restoreStdLog 由 l 重定向时恢复标准库 log 的输出：slog 默认 logger 仍由本包设置时交还给 slog，否则还原原始输出
+func restoreStdLog(l *Logger) {
+	logRedirect.mu.Lock()
+	defer logRedirect.mu.Unlock()
+
+	if logRedirect.stdOwner != l {
+		return
+	}
+	logRedirect.stdOwner = nil
+	logRedirect.stdWriter = nil
+	applyLogLocked()
+}
+
+// stdLogWriter 将标准库 log 的每一行写为一条日志
+type stdLogWriter struct {
+	logger *zap.Logger
+	level  zapcore.Level
+	named  sync.Map // 组件名 -> *zap.Logger
+}
+
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimSuffix(string(p), "\n")
+	name, msg := splitStdLogPrefix(msg)
+
+	logger := w.logger
+	if name != "" {
+		if v, ok := w.named.Load(name); ok {
+			logger = v.(*zap.Logger)
+		} else {
+			logger = w.logger.Named(name)
+			w.named.Store(name, logger)
+		}
+	}
+
+	if ce := logger.Check(w.level, msg); ce != nil {
+		ce.Write()
+	}
+	return len(p), nil
+}
+
+// splitStdLogPrefix 拆分 "[component] message" 形式的前缀，组件名不能包含空白
+func splitStdLogPrefix(msg string) (name, rest string) {
+	if !strings.HasPrefix(msg, "[") {
+		return "", msg
+	}
+	end := strings.IndexByte(msg, ']')
+	if end <= 1 || end > maxStdLogComponent+1 {
+		return "", msg
+	}
+	name = msg[1:end]
+	if strings.ContainsAny(name, " \t[") {
+		return "", msg
+	}
+	return name, strings.TrimLeft(msg[end+1:], " ")
+}
@@ -0,0 +1,65 @@
+package logger_test
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/constellation39/framework/logger"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestCloseRestoresStdLogAndSlog(t *testing.T) {
+	var original bytes.Buffer
+	prevOut, prevFlags, prevSlog := log.Writer(), log.Flags(), slog.Default()
+	log.SetOutput(&original)
+	log.SetFlags(0)
+	t.Cleanup(func() {
+		log.SetOutput(prevOut)
+		log.SetFlags(prevFlags)
+		slog.SetDefault(prevSlog)
+	})
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	err := logger.Init(
+		logger.WithFile(false, "", ""),
+		logger.WithConsole(false, false),
+		logger.WithCore(core),
+		logger.WithSlogDefault(true),
+		logger.WithStdLog(true, "warn"),
+	)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	t.Cleanup(func() { logger.SetGlobal(nil) })
+
+	log.Print("from log")
+	slog.Info("from slog")
+	if got := logs.FilterMessage("from log").All(); len(got) != 1 || got[0].Level != zapcore.WarnLevel {
+		t.Errorf("std log entry = %v, want one warn entry", got)
+	}
+	if logs.FilterMessage("from slog").Len() != 1 {
+		t.Errorf("slog entry not captured")
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// 关闭后两者都回到接管前的输出，而不是写入已关闭的 logger
+	log.Print("after close")
+	slog.Info("slog after close")
+	if logs.FilterMessageSnippet("after close").Len() != 0 {
+		t.Errorf("entries written to closed logger: %v", logs.FilterMessageSnippet("after close").All())
+	}
+	out := original.String()
+	if !strings.Contains(out, "after close") || !strings.Contains(out, "slog after close") {
+		t.Errorf("original output = %q", out)
+	}
+	if log.Flags() != 0 {
+		t.Errorf("log flags = %d, want 0", log.Flags())
+	}
+}
@@ -0,0 +1,406 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SyslogConfig syslog 输出配置，仅 OutputConfig.Type 为 syslog 时有效，地址取自 OutputConfig.Address
+type SyslogConfig struct {
+	Network    string `json:"network" yaml:"network"`         // 网络类型: udp(默认), tcp, unix, unixgram
+	Format     string `json:"format" yaml:"format"`           // 报文格式: rfc5424(默认), rfc3164
+	Facility   string `json:"facility" yaml:"facility"`       // 设施: kern, user(默认), daemon, auth, local0 ~ local7 等
+	AppName    string `json:"app_name" yaml:"app_name"`       // 应用名，默认为进程名
+	Hostname   string `json:"hostname" yaml:"hostname"`       // 主机名，默认为 os.Hostname()
+	BufferSize int    `json:"buffer_size" yaml:"buffer_size"` // 连接断开期间最多缓冲的条数，默认 1000，超出时丢弃最旧的（计入 logger_sink_dropped_entries_total）
+}
+
+const (
+	syslogFormat5424 = "rfc5424"
+	syslogFormat3164 = "rfc3164"
+
+	defaultSyslogBufferSize = 1000
+
+	syslogMinBackoff = 500 * time.Millisecond
+	syslogMaxBackoff = 30 * time.Second
+)
+
+// syslogFacilities 设施名到编码的映射
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3,
+	"auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// syslogSeverity 将 zap 级别映射为 syslog 严重程度
+func syslogSeverity(lvl zapcore.Level) int {
+	switch lvl {
+	case zapcore.DebugLevel:
+		return 7 // debug
+	case zapcore.InfoLevel:
+		return 6 // informational
+	case zapcore.WarnLevel:
+		return 4 // warning
+	case zapcore.ErrorLevel:
+		return 3 // err
+	case zapcore.DPanicLevel:
+		return 2 // crit
+	case zapcore.PanicLevel:
+		return 1 // alert
+	case zapcore.FatalLevel:
+		return 0 // emerg
+	default:
+		return 5 // notice
+	}
+}
+
+// buildSyslogCore 构建 syslog 输出 core
+func buildSyslogCore(cfg *Config, out *OutputConfig, enabler zapcore.LevelEnabler) (zapcore.Core, *syslogWriter, error) {
+	sc := out.Syslog
+
+	network := strings.ToLower(firstNonEmpty(sc.Network, "udp"))
+	switch network {
+	case "udp", "udp4", "udp6", "tcp", "tcp4", "tcp6", "unix", "unixgram":
+	default:
+		return nil, nil, fmt.Errorf("unknown syslog network: %q", sc.Network)
+	}
+	if out.Address == "" {
+		return nil, nil, fmt.Errorf("address is required for syslog output")
+	}
+
+	format := strings.ToLower(firstNonEmpty(sc.Format, syslogFormat5424))
+	if format != syslogFormat5424 && format != syslogFormat3164 {
+		return nil, nil, fmt.Errorf("unknown syslog format: %q", sc.Format)
+	}
+
+	facility, ok := syslogFacilities[strings.ToLower(firstNonEmpty(sc.Facility, "user"))]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown syslog facility: %q", sc.Facility)
+	}
+
+	hostname := sc.Hostname
+	if hostname == "" {
+		hostname, _ = os.Hostname()
+	}
+	appName := firstNonEmpty(sc.AppName, filepath.Base(os.Args[0]))
+
+	bufferSize := sc.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultSyslogBufferSize
+	}
+
+	encoder, err := buildEncoder(cfg, out)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	w := newSyslogWriter(network, out.Address, bufferSize)
+	core := &syslogCore{
+		LevelEnabler: enabler,
+		enc:          encoder,
+		out:          w,
+		format:       format,
+		facility:     facility,
+		hostname:     syslogHeaderField(hostname, 255),
+		appName:      syslogHeaderField(appName, 48),
+		procID:       strconv.Itoa(os.Getpid()),
+	}
+	return core, w, nil
+}
+
+// syslogHeaderField 将值转为合法的 syslog 头部字段：可打印 ASCII、无空格、限制长度，为空时为 "-"
+func syslogHeaderField(s string, maxLen int) string {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s) && len(b) < maxLen; i++ {
+		if c := s[i]; c > ' ' && c < 0x7f {
+			b = append(b, c)
+		}
+	}
+	if len(b) == 0 {
+		return "-"
+	}
+	return string(b)
+}
+
+// syslogCore 将日志编码后加上 syslog 头部发送
+type syslogCore struct {
+	zapcore.LevelEnabler
+	enc zapcore.Encoder
+	out *syslogWriter
+
+	format   string
+	facility int
+	hostname string
+	appName  string
+	procID   string
+}
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.enc = c.enc.Clone()
+	for i := range fields {
+		fields[i].AddTo(clone.enc)
+	}
+	return &clone
+}
+
+func (c *syslogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *syslogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	body, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	defer body.Free()
+
+	msg := c.header(ent)
+	msg = append(msg, strings.TrimRight(body.String(), "\r\n")...)
+
+	if err := c.out.send(msg, ent.Level); err != nil {
+		return err
+	}
+	if ent.Level > zapcore.ErrorLevel {
+		// 进程可能即将退出，尽量把缓冲发出去
+		_ = c.Sync()
+	}
+	return nil
+}
+
+func (c *syslogCore) Sync() error {
+	return c.out.Sync()
+}
+
+// header 生成 syslog 头部
+func (c *syslogCore) header(ent zapcore.Entry) []byte {
+	pri := c.facility*8 + syslogSeverity(ent.Level)
+	b := make([]byte, 0, 128)
+
+	if c.format == syslogFormat3164 {
+		// <PRI>Mmm dd hh:mm:ss HOSTNAME TAG[PID]: MSG
+		b = append(b, '<')
+		b = strconv.AppendInt(b, int64(pri), 10)
+		b = append(b, '>')
+		b = ent.Time.AppendFormat(b, time.Stamp)
+		b = append(b, ' ')
+		b = append(b, c.hostname...)
+		b = append(b, ' ')
+		b = append(b, c.appName...)
+		b = append(b, '[')
+		b = append(b, c.procID...)
+		b = append(b, "]: "...)
+		return b
+	}
+
+	// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+	b = append(b, '<')
+	b = strconv.AppendInt(b, int64(pri), 10)
+	b = append(b, ">1 "...)
+	b = ent.Time.AppendFormat(b, "2006-01-02T15:04:05.000000Z07:00")
+	b = append(b, ' ')
+	b = append(b, c.hostname...)
+	b = append(b, ' ')
+	b = append(b, c.appName...)
+	b = append(b, ' ')
+	b = append(b, c.procID...)
+	b = append(b, ' ')
+	b = append(b, syslogHeaderField(ent.LoggerName, 32)...)
+	b = append(b, " - "...)
+	return b
+}
+
+// errSyslogNotConnected 连接尚未恢复，消息已进入缓冲
+var errSyslogNotConnected = errors.New("syslog: not connected")
+
+// syslogWriter 带自动重连与有界缓冲的 syslog 传输层。
+// 流式连接（tcp、unix）使用 RFC 6587 octet-counting 分帧，数据报连接每条消息一个报文
+type syslogWriter struct {
+	network string
+	address string
+	stream  bool
+
+	mu       sync.Mutex
+	conn     net.Conn
+	pending  []syslogFrame // 断线期间缓冲的消息
+	limit    int
+	backoff  time.Duration
+	nextDial time.Time
+	closed   bool
+
+	sink    string   // 指标中的输出名
+	metrics *metrics // 实际发出或丢弃时计数，为 nil 时不统计
+}
+
+// syslogFrame 一条已分帧的消息
+type syslogFrame struct {
+	data  []byte
+	level zapcore.Level
+}
+
+func newSyslogWriter(network, address string, limit int) *syslogWriter {
+	stream := strings.HasPrefix(network, "tcp") || network == "unix"
+	return &syslogWriter{
+		network: network,
+		address: address,
+		stream:  stream,
+		limit:   limit,
+	}
+}
+
+// reportTo 实现 sinkReporter：消息进入缓冲时不计为已写入，实际发出或丢弃时才计数
+func (w *syslogWriter) reportTo(name string, m *metrics) {
+	w.sink = name
+	w.metrics = m
+}
+
+// send 发送一条消息，连接不可用时放入缓冲等待重连后补发
+func (w *syslogWriter) send(msg []byte, lvl zapcore.Level) error {
+	frame := syslogFrame{data: msg, level: lvl}
+	if w.stream {
+		frame.data = make([]byte, 0, len(msg)+8)
+		frame.data = strconv.AppendInt(frame.data, int64(len(msg)), 10)
+		frame.data = append(frame.data, ' ')
+		frame.data = append(frame.data, msg...)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return os.ErrClosed
+	}
+
+	if err := w.flushLocked(); err == nil {
+		if err = w.writeLocked(frame); err == nil {
+			return nil
+		}
+	}
+	w.enqueueLocked(frame)
+	return nil
+}
+
+// Sync 尝试补发缓冲中的消息，连接不可用时保留缓冲且不返回错误
+func (w *syslogWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.closed && len(w.pending) > 0 {
+		_ = w.flushLocked()
+	}
+	return nil
+}
+
+// Close 尽量补发缓冲后关闭连接
+func (w *syslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	_ = w.flushLocked()
+	w.closed = true
+	w.lostLocked(len(w.pending))
+	w.pending = nil
+
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// flushLocked 确保连接可用并按顺序补发缓冲
+func (w *syslogWriter) flushLocked() error {
+	if err := w.connectLocked(); err != nil {
+		return err
+	}
+	for len(w.pending) > 0 {
+		if err := w.writeLocked(w.pending[0]); err != nil {
+			return err
+		}
+		w.pending[0] = syslogFrame{}
+		w.pending = w.pending[1:]
+	}
+	return nil
+}
+
+// connectLocked 建立连接，失败后按指数退避推迟下次重连
+func (w *syslogWriter) connectLocked() error {
+	if w.conn != nil {
+		return nil
+	}
+	now := time.Now()
+	if now.Before(w.nextDial) {
+		return errSyslogNotConnected
+	}
+
+	conn, err := net.DialTimeout(w.network, w.address, netDialTimeout)
+	if err != nil {
+		w.scheduleRetryLocked(now)
+		return fmt.Errorf("failed to dial syslog %s %s: %w", w.network, w.address, err)
+	}
+	w.conn = conn
+	w.backoff = 0
+	return nil
+}
+
+// writeLocked 写入一帧，失败时断开连接以便下次重连
+func (w *syslogWriter) writeLocked(frame syslogFrame) error {
+	if w.conn == nil {
+		return errSyslogNotConnected
+	}
+	_ = w.conn.SetWriteDeadline(time.Now().Add(netDialTimeout))
+	if _, err := w.conn.Write(frame.data); err != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+		w.scheduleRetryLocked(time.Now())
+		return err
+	}
+	if w.metrics != nil {
+		w.metrics.written.inc(sinkKey{sink: w.sink, level: frame.level})
+	}
+	return nil
+}
+
+func (w *syslogWriter) scheduleRetryLocked(now time.Time) {
+	if w.backoff == 0 {
+		w.backoff = syslogMinBackoff
+	} else if w.backoff *= 2; w.backoff > syslogMaxBackoff {
+		w.backoff = syslogMaxBackoff
+	}
+	w.nextDial = now.Add(w.backoff)
+}
+
+// enqueueLocked 放入缓冲，超出上限时丢弃最旧的消息
+func (w *syslogWriter) enqueueLocked(frame syslogFrame) {
+	if len(w.pending) >= w.limit {
+		drop := len(w.pending) - w.limit + 1
+		w.pending = append(w.pending[:0], w.pending[drop:]...)
+		w.lostLocked(drop)
+	}
+	w.pending = append(w.pending, frame)
+}
+
+// lostLocked 统计未能送达而丢弃的消息
+func (w *syslogWriter) lostLocked(n int) {
+	if w.metrics != nil && n > 0 {
+		w.metrics.lost.add(w.sink, uint64(n))
+	}
+}
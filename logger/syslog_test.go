@@ -0,0 +1,219 @@
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// readOctetFrame 读取一个 RFC 6587 octet-counting 帧
+func readOctetFrame(r *bufio.Reader) (string, error) {
+	prefix, err := r.ReadString(' ')
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(prefix, " "))
+	if err != nil {
+		return "", fmt.Errorf("invalid frame length %q", prefix)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// syslogServer 接受 TCP 连接，将每个连接读到的帧依次发到 frames
+type syslogServer struct {
+	ln     net.Listener
+	conns  chan net.Conn
+	frames chan string
+}
+
+func newSyslogServer(t *testing.T) *syslogServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &syslogServer{ln: ln, conns: make(chan net.Conn, 4), frames: make(chan string, 64)}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.conns <- conn
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					frame, err := readOctetFrame(r)
+					if err != nil {
+						return
+					}
+					s.frames <- frame
+				}
+			}()
+		}
+	}()
+	return s
+}
+
+func (s *syslogServer) next(t *testing.T) string {
+	t.Helper()
+	select {
+	case f := <-s.frames:
+		return f
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for syslog frame")
+		return ""
+	}
+}
+
+func TestSyslogOctetCountedFraming(t *testing.T) {
+	srv := newSyslogServer(t)
+
+	l, err := New(WithOutputs(OutputConfig{
+		Type:     OutputSyslog,
+		Address:  srv.ln.Addr().String(),
+		Encoding: "json",
+		Syslog: SyslogConfig{
+			Network:  "tcp",
+			Facility: "local0",
+			AppName:  "demo app",
+			Hostname: "host1",
+		},
+	}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer l.Close()
+
+	// 消息中的换行不影响分帧
+	l.Named("db").Warn("line1\nline2")
+	l.Info("second")
+
+	first := srv.next(t)
+	// local0(16)*8 + warning(4) = 132，应用名中的空格被去掉
+	if want := "<132>1 "; !strings.HasPrefix(first, want) {
+		t.Errorf("frame = %q, want prefix %q", first, want)
+	}
+	if !strings.Contains(first, " host1 demoapp ") || !strings.Contains(first, " db - {") {
+		t.Errorf("frame header = %q", first)
+	}
+	if !strings.Contains(first, `line1\nline2`) {
+		t.Errorf("frame body = %q", first)
+	}
+	if second := srv.next(t); !strings.HasPrefix(second, "<134>1 ") || !strings.Contains(second, `"second"`) {
+		t.Errorf("second frame = %q", second)
+	}
+}
+
+func TestSyslogDatagram(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+
+	w := newSyslogWriter("udp", pc.LocalAddr().String(), 10)
+	defer w.Close()
+	if err := w.send([]byte("<14>1 msg"), zapcore.InfoLevel); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	buf := make([]byte, 1024)
+	_ = pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 数据报每条消息一个报文，不加长度前缀
+	if got := string(buf[:n]); got != "<14>1 msg" {
+		t.Errorf("datagram = %q", got)
+	}
+}
+
+func TestSyslogReconnect(t *testing.T) {
+	srv := newSyslogServer(t)
+	w := newSyslogWriter("tcp", srv.ln.Addr().String(), 10)
+	defer w.Close()
+
+	if err := w.send([]byte("before"), zapcore.InfoLevel); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if got := srv.next(t); got != "before" {
+		t.Fatalf("frame = %q", got)
+	}
+
+	// 服务端断开，写入失败后断开连接并进入退避
+	conn := <-srv.conns
+	_ = conn.Close()
+	var probe string
+	for i := 0; ; i++ {
+		if i == 200 {
+			t.Fatal("write to closed connection never failed")
+		}
+		probe = fmt.Sprintf("probe %d", i)
+		_ = w.send([]byte(probe), zapcore.InfoLevel)
+		w.mu.Lock()
+		disconnected := w.conn == nil
+		w.mu.Unlock()
+		if disconnected {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	_ = w.send([]byte("after"), zapcore.InfoLevel)
+
+	// 退避结束后重连，缓冲中的消息按顺序补发
+	time.Sleep(syslogMinBackoff + 100*time.Millisecond)
+	if err := w.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if got := srv.next(t); got != probe {
+		t.Errorf("first frame after reconnect = %q, want %q", got, probe)
+	}
+	if got := srv.next(t); got != "after" {
+		t.Errorf("second frame after reconnect = %q, want %q", got, "after")
+	}
+}
+
+func TestSyslogBufferOverflow(t *testing.T) {
+	m := &metrics{}
+	w := newSyslogWriter("tcp", closedAddr(t), 3)
+	w.reportTo("sys", m)
+
+	for i := 1; i <= 5; i++ {
+		if err := w.send([]byte(fmt.Sprintf("msg %d", i)), zapcore.InfoLevel); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+
+	// 超出上限时丢弃最旧的消息
+	w.mu.Lock()
+	var pending []string
+	for _, f := range w.pending {
+		pending = append(pending, string(f.data))
+	}
+	w.mu.Unlock()
+	if got, want := strings.Join(pending, ","), "5 msg 3,5 msg 4,5 msg 5"; got != want {
+		t.Errorf("pending = %q, want %q", got, want)
+	}
+
+	var lost uint64
+	m.lost.each(func(sink string, n uint64) { lost += n })
+	if lost != 2 {
+		t.Errorf("lost = %d, want 2", lost)
+	}
+}
@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+// 日志中 trace 关联字段的 key
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+// TraceExtractor 从 ctx 中提取当前 span 的 trace ID 与 span ID，没有 span 时返回 ok=false。
+// 会在每次通过 ctx 取 logger 时调用，实现需足够轻量
+type TraceExtractor func(ctx context.Context) (traceID, spanID string, ok bool)
+
+var traceExtractor atomic.Pointer[TraceExtractor]
+
+func init() {
+	SetTraceExtractor(TraceparentExtractor)
+}
+
+// SetTraceExtractor 设置 trace 提取器，默认为 TraceparentExtractor；传入 nil 关闭 trace 关联
+func SetTraceExtractor(fn TraceExtractor) {
+	if fn == nil {
+		traceExtractor.Store(nil)
+		return
+	}
+	traceExtractor.Store(&fn)
+}
+
+// traceFields 提取 ctx 中的 trace 字段，没有 span 时不产生任何分配
+func traceFields(ctx context.Context) (traceID, spanID zap.Field, ok bool) {
+	fn := traceExtractor.Load()
+	if fn == nil {
+		return zap.Field{}, zap.Field{}, false
+	}
+	tid, sid, ok := (*fn)(ctx)
+	if !ok {
+		return zap.Field{}, zap.Field{}, false
+	}
+	return zap.String(TraceIDKey, tid), zap.String(SpanIDKey, sid), true
+}
+
+// W3C Trace Context 实现
+
+// traceparentKey context 中存放 traceparent 的 key
+type traceparentKey struct{}
+
+// traceparent 解析后的 W3C traceparent
+type traceparent struct {
+	traceID string
+	spanID  string
+}
+
+// WithTraceparent 解析 W3C traceparent 头并存入 ctx，头部无效时原样返回 ctx
+func WithTraceparent(ctx context.Context, header string) context.Context {
+	traceID, spanID, ok := ParseTraceparent(header)
+	if !ok {
+		return ctx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, traceparentKey{}, traceparent{traceID: traceID, spanID: spanID})
+}
+
+// TraceparentExtractor 从 WithTraceparent 存入的信息中提取 trace ID 与 span ID
+func TraceparentExtractor(ctx context.Context) (traceID, spanID string, ok bool) {
+	if ctx == nil {
+		return "", "", false
+	}
+	tp, ok := ctx.Value(traceparentKey{}).(traceparent)
+	if !ok {
+		return "", "", false
+	}
+	return tp.traceID, tp.spanID, true
+}
+
+// ParseTraceparent 解析 W3C traceparent 头，格式为 version-traceid-parentid-flags，
+// 如 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func ParseTraceparent(header string) (traceID, spanID string, ok bool) {
+	header = strings.TrimSpace(header)
+	parts := strings.Split(header, "-")
+	if len(parts) < 4 {
+		return "", "", false
+	}
+
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if len(version) != 2 || !isLowerHex(version) || version == "ff" {
+		return "", "", false
+	}
+	// 版本 00 只允许 4 段
+	if version == "00" && len(parts) != 4 {
+		return "", "", false
+	}
+	if len(traceID) != 32 || !isLowerHex(traceID) || isAllZero(traceID) {
+		return "", "", false
+	}
+	if len(spanID) != 16 || !isLowerHex(spanID) || isAllZero(spanID) {
+		return "", "", false
+	}
+	if len(flags) != 2 || !isLowerHex(flags) {
+		return "", "", false
+	}
+	return traceID, spanID, true
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isAllZero(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '0' {
+			return false
+		}
+	}
+	return true
+}
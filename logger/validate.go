@@ -0,0 +1,366 @@
+package logger
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/constellation39/framework/logger/rotate"
+	"go.uber.org/zap/zapcore"
+)
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	Field string // 字段路径，如 "outputs[0].file.rotation_time"
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError 配置校验发现的全部问题
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid logger config (%d problems):", len(e.Errors))
+	for _, fe := range e.Errors {
+		b.WriteString("\n  ")
+		b.WriteString(fe.Error())
+	}
+	return b.String()
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, fe := range e.Errors {
+		errs[i] = fe
+	}
+	return errs
+}
+
+// validator 收集校验错误
+type validator struct {
+	errs []*FieldError
+}
+
+func (v *validator) addf(field, format string, args ...interface{}) {
+	v.errs = append(v.errs, &FieldError{Field: field, Err: fmt.Errorf(format, args...)})
+}
+
+func (v *validator) check(field string, err error) {
+	if err != nil {
+		v.errs = append(v.errs, &FieldError{Field: field, Err: err})
+	}
+}
+
+func (v *validator) level(field, level string) {
+	_, err := parseLevel(level)
+	v.check(field, err)
+}
+
+func (v *validator) nonNegative(field string, n int64) {
+	if n < 0 {
+		v.addf(field, "must not be negative, got %d", n)
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: v.errs}
+}
+
+// Validate 校验配置，一次返回所有问题，错误类型为 *ValidationError
+func (c *Config) Validate() error {
+	var v validator
+
+	v.level("level", c.Level)
+	validateEncoding(&v, "encoding", c.Encoding, false)
+
+	patterns := make([]string, 0, len(c.ModuleLevels))
+	for pattern := range c.ModuleLevels {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		field := "module_levels[" + pattern + "]"
+		v.check(field, validateModulePattern(strings.TrimSpace(pattern)))
+		v.level(field, c.ModuleLevels[pattern])
+	}
+
+	// 堆栈与采样
+	if c.EnableStacktrace {
+		v.level("stacktrace_level", c.StacktraceLevel)
+	}
+	v.nonNegative("max_stack_frames", int64(c.MaxStackFrames))
+	validateStackFormat(&v, "stack_format", c.StackFormat)
+	v.nonNegative("stack_head_frames", int64(c.StackHeadFrames))
+	v.nonNegative("stack_tail_frames", int64(c.StackTailFrames))
+	v.nonNegative("caller_skip", int64(c.CallerSkip))
+	validateCallerFormat(&v, "caller_format", c.CallerFormat)
+	v.nonNegative("sampling_initial", int64(c.SamplingInitial))
+	v.nonNegative("sampling_after", int64(c.SamplingAfter))
+	if c.EnableSampling && c.SamplingInitial == 0 {
+		v.addf("sampling_initial", "must be positive when sampling is enabled")
+	}
+
+	if c.RateLimit.Enabled {
+		c.validateRateLimit(&v)
+	}
+
+	// 异步写入
+	if c.EnableAsync {
+		if c.AsyncQueueSize <= 0 {
+			v.addf("async_queue_size", "must be positive, got %d", c.AsyncQueueSize)
+		}
+		_, err := parseOverflowPolicy(c.AsyncOverflow)
+		v.check("async_overflow", err)
+		v.level("async_drop_level", c.AsyncDropLevel)
+	}
+
+	v.nonNegative("crash_max_reports", int64(c.CrashMaxReports))
+
+	if c.RedirectStdLog {
+		v.level("std_log_level", c.StdLogLevel)
+	}
+
+	c.validateRedaction(&v)
+
+	// 输出
+	if len(c.Outputs) == 0 {
+		if !c.EnableFile && !c.EnableConsole && len(c.cores) == 0 {
+			v.addf("outputs", "at least one output must be enabled")
+		}
+		if c.EnableFile {
+			c.validateFile(&v, "", FileOutputConfig{})
+			v.nonNegative("error_max_age", int64(c.ErrorMaxAge))
+			if c.EnableErrorFile {
+				for i, level := range c.ErrorFileLevels {
+					v.level(fmt.Sprintf("error_file_levels[%d]", i), level)
+				}
+			}
+		}
+	}
+	for i := range c.Outputs {
+		c.validateOutput(&v, fmt.Sprintf("outputs[%d]", i), &c.Outputs[i])
+	}
+
+	return v.err()
+}
+
+// validateEncoding 校验编码格式，allowEmpty 为 true 时允许为空
+func validateEncoding(v *validator, field, encoding string, allowEmpty bool) {
+	switch strings.ToLower(encoding) {
+	case "json", "console":
+	case "":
+		if !allowEmpty {
+			v.addf(field, "must not be empty")
+		}
+	default:
+		v.addf(field, "unknown encoding %q, expected json or console", encoding)
+	}
+}
+
+// validateCallerFormat 校验调用位置格式，允许为空
+func validateCallerFormat(v *validator, field, format string) {
+	switch strings.ToLower(format) {
+	case "", CallerShort, CallerFull, CallerModule, CallerTrim:
+	default:
+		v.addf(field, "unknown caller format %q, expected short, full, module or trim", format)
+	}
+}
+
+// validateStackFormat 校验堆栈格式，允许为空
+func validateStackFormat(v *validator, field, format string) {
+	switch strings.ToLower(format) {
+	case "", StackText, StackStructured:
+	default:
+		v.addf(field, "unknown stack format %q, expected text or structured", format)
+	}
+}
+
+func (c *Config) validateRateLimit(v *validator) {
+	r := &c.RateLimit
+	v.nonNegative("rate_limit.window", int64(r.Window))
+	v.nonNegative("rate_limit.max_keys", int64(r.MaxKeys))
+	for i, key := range r.KeyFields {
+		if strings.TrimSpace(key) == "" {
+			v.addf(fmt.Sprintf("rate_limit.key_fields[%d]", i), "must not be empty")
+		}
+	}
+
+	levels := make([]string, 0, len(r.Budgets))
+	for level := range r.Budgets {
+		levels = append(levels, level)
+	}
+	sort.Strings(levels)
+	for _, level := range levels {
+		field := "rate_limit.budgets[" + level + "]"
+		v.level(field, level)
+		v.nonNegative(field, int64(r.Budgets[level]))
+	}
+}
+
+func (c *Config) validateRedaction(v *validator) {
+	r := &c.Redaction
+	switch strings.ToLower(r.Mode) {
+	case "", RedactMask, RedactHash:
+	default:
+		v.addf("redaction.mode", "unknown redaction mode %q, expected mask or hash", r.Mode)
+	}
+	for i, key := range r.Keys {
+		if _, err := path.Match(strings.ToLower(strings.TrimSpace(key)), ""); err != nil {
+			v.addf(fmt.Sprintf("redaction.keys[%d]", i), "invalid pattern %q: %w", key, err)
+		}
+	}
+	for i, p := range r.Patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			v.addf(fmt.Sprintf("redaction.patterns[%d]", i), "invalid regexp: %w", err)
+		}
+	}
+}
+
+// validateFile 校验文件输出生效后的配置。prefix 为空时校验平铺字段，
+// 否则校验 prefix 下的 FileOutputConfig，未设置的字段沿用 parent 与 Config
+func (c *Config) validateFile(v *validator, prefix string, fc FileOutputConfig) {
+	name := func(field, flatField string) string {
+		if prefix == "" {
+			return flatField
+		}
+		return prefix + "." + field
+	}
+
+	dir := firstNonEmpty(fc.Dir, c.LogDir)
+	filename := firstNonEmpty(fc.Filename, c.Filename)
+	pattern := firstNonEmpty(fc.Pattern, c.FilePattern, "%Y%m%d.%N")
+
+	if strings.TrimSpace(dir) == "" {
+		v.addf(name("dir", "log_dir"), "must not be empty")
+	}
+	if strings.TrimSpace(filename) == "" {
+		v.addf(name("filename", "filename"), "must not be empty")
+	} else if strings.ContainsAny(filename, `/\`) {
+		v.addf(name("filename", "filename"), "must not contain path separators, got %q", filename)
+	}
+	if err := rotate.ValidatePattern(filename + "." + pattern + ".log"); err != nil {
+		v.check(name("pattern", "file_pattern"), err)
+	}
+
+	rotationTime := fc.RotationTime
+	if rotationTime == 0 {
+		rotationTime = c.RotationTime
+	}
+	if rotationTime <= 0 {
+		v.addf(name("rotation_time", "rotation_time"), "must be positive, got %d", rotationTime)
+	}
+
+	v.nonNegative(name("max_age", "max_age"), int64(firstNonZero(fc.MaxAge, c.MaxAge)))
+	v.nonNegative(name("rotation_size", "rotation_size"), firstNonZero(fc.RotationSize, c.RotationSize))
+	v.nonNegative(name("max_total_size", "max_total_size"), firstNonZero(fc.MaxTotalSize, c.MaxTotalSize))
+}
+
+func (c *Config) validateOutput(v *validator, prefix string, out *OutputConfig) {
+	outType := strings.ToLower(out.Type)
+	switch outType {
+	case OutputFile, OutputStdout, OutputStderr, OutputTCP, OutputUDP, OutputUnix, OutputSyslog:
+	case "":
+		v.addf(prefix+".type", "must not be empty")
+	default:
+		v.addf(prefix+".type", "unknown output type %q", out.Type)
+	}
+
+	// 级别区间
+	var minErr, maxErr error
+	minLevel, maxLevel := zapcore.DebugLevel, zapcore.FatalLevel
+	if out.MinLevel != "" {
+		minLevel, minErr = parseLevel(out.MinLevel)
+		v.check(prefix+".min_level", minErr)
+	}
+	if out.MaxLevel != "" {
+		maxLevel, maxErr = parseLevel(out.MaxLevel)
+		v.check(prefix+".max_level", maxErr)
+	}
+	if minErr == nil && maxErr == nil && minLevel > maxLevel {
+		v.addf(prefix+".min_level", "%s is above max_level %s", out.MinLevel, out.MaxLevel)
+	}
+
+	// 编码
+	validateEncoding(v, prefix+".encoding", out.Encoding, true)
+	if _, err := parseTimeEncoder(out.Encoder.TimeFormat); err != nil {
+		v.check(prefix+".encoder.time_format", err)
+	}
+	if out.Encoder.LevelFormat != "" {
+		_, err := parseLevelEncoder(out.Encoder.LevelFormat)
+		v.check(prefix+".encoder.level_format", err)
+	}
+	_, err := parseDurationEncoder(out.Encoder.DurationFormat)
+	v.check(prefix+".encoder.duration_format", err)
+	validateCallerFormat(v, prefix+".encoder.caller_format", out.Encoder.CallerFormat)
+	validateStackFormat(v, prefix+".encoder.stack_format", out.Encoder.StackFormat)
+
+	switch outType {
+	case OutputFile:
+		c.validateFile(v, prefix+".file", out.File)
+		for i := range out.File.Routes {
+			route := &out.File.Routes[i]
+			routePrefix := fmt.Sprintf("%s.file.routes[%d]", prefix, i)
+			if len(route.Levels) == 0 {
+				v.addf(routePrefix+".levels", "must not be empty")
+			}
+			for j, level := range route.Levels {
+				v.level(fmt.Sprintf("%s.levels[%d]", routePrefix, j), level)
+			}
+			fc := inheritFileConfig(route.File, out.File)
+			if route.File.Filename == "" {
+				fc.Filename = firstNonEmpty(out.File.Filename, c.Filename) + ".error"
+			}
+			c.validateFile(v, routePrefix+".file", fc)
+		}
+	case OutputTCP, OutputUDP, OutputUnix:
+		if out.Address == "" {
+			v.addf(prefix+".address", "is required for %s output", outType)
+		}
+	case OutputSyslog:
+		if out.Address == "" {
+			v.addf(prefix+".address", "is required for syslog output")
+		}
+		sc := &out.Syslog
+		switch strings.ToLower(sc.Network) {
+		case "", "udp", "udp4", "udp6", "tcp", "tcp4", "tcp6", "unix", "unixgram":
+		default:
+			v.addf(prefix+".syslog.network", "unknown syslog network %q", sc.Network)
+		}
+		switch strings.ToLower(sc.Format) {
+		case "", syslogFormat5424, syslogFormat3164:
+		default:
+			v.addf(prefix+".syslog.format", "unknown syslog format %q", sc.Format)
+		}
+		if sc.Facility != "" {
+			if _, ok := syslogFacilities[strings.ToLower(sc.Facility)]; !ok {
+				v.addf(prefix+".syslog.facility", "unknown syslog facility %q", sc.Facility)
+			}
+		}
+		v.nonNegative(prefix+".syslog.buffer_size", int64(sc.BufferSize))
+	}
+}
+
+// firstNonZero 返回第一个非零值
+func firstNonZero[T int | int64 | uint](values ...T) T {
+	for _, v := range values {
+		if v != 0 {
+			return v
+		}
+	}
+	return 0
+}
@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// validationFields 返回校验错误中的全部字段路径
+func validationFields(t *testing.T, err error) []string {
+	t.Helper()
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("error %v is not a *ValidationError", err)
+	}
+	fields := make([]string, len(verr.Errors))
+	for i, fe := range verr.Errors {
+		fields[i] = fe.Field
+	}
+	return fields
+}
+
+func TestValidateDefaultConfig(t *testing.T) {
+	if err := defaultConfig().Validate(); err != nil {
+		t.Errorf("default config: %v", err)
+	}
+}
+
+func TestValidateOutputLevelRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max string
+		wantErr  bool
+	}{
+		{"unset", "", "", false},
+		{"min and max debug", "debug", "debug", false},
+		{"max debug", "", "debug", false},
+		{"min fatal", "fatal", "", false},
+		{"min equals max", "warn", "warn", false},
+		{"min above max", "warn", "debug", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.Outputs = []OutputConfig{{Type: OutputStderr, MinLevel: tt.min, MaxLevel: tt.max}}
+			err := cfg.Validate()
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if got := validationFields(t, err); !reflect.DeepEqual(got, []string{"outputs[0].min_level"}) {
+				t.Errorf("fields = %q, want [outputs[0].min_level]", got)
+			}
+		})
+	}
+}
+
+func TestValidateInvalidLevelSkipsRangeCheck(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Outputs = []OutputConfig{{Type: OutputStderr, MinLevel: "loud", MaxLevel: "debug"}}
+
+	// 无法解析的级别只报告一次
+	if got := validationFields(t, cfg.Validate()); !reflect.DeepEqual(got, []string{"outputs[0].min_level"}) {
+		t.Errorf("fields = %q, want [outputs[0].min_level]", got)
+	}
+}
+
+func TestValidateAggregatesErrors(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Level = "loud"
+	cfg.EnableSampling = true
+	cfg.SamplingInitial = 0
+	cfg.ModuleLevels = map[string]string{"db": "verbose"}
+	cfg.Outputs = []OutputConfig{
+		{Type: OutputStdout},
+		{Type: OutputTCP, MinLevel: "error", MaxLevel: "info"},
+		{Type: OutputFile, File: FileOutputConfig{RotationTime: -1, Routes: []FileRouteConfig{{}}}},
+	}
+
+	err := cfg.Validate()
+	want := []string{
+		"level",
+		"module_levels[db]",
+		"sampling_initial",
+		"outputs[1].min_level",
+		"outputs[1].address",
+		"outputs[2].file.rotation_time",
+		"outputs[2].file.routes[0].levels",
+		"outputs[2].file.routes[0].file.rotation_time",
+	}
+	if got := validationFields(t, err); !reflect.DeepEqual(got, want) {
+		t.Errorf("fields = %q\nwant %q", got, want)
+	}
+
+	// 消息列出所有问题
+	if msg := err.Error(); !strings.HasPrefix(msg, "invalid logger config (8 problems):") {
+		t.Errorf("message = %q", msg)
+	}
+
+	// 可以通过 errors.As 取得单个字段的错误
+	var fe *FieldError
+	if !errors.As(err, &fe) || fe.Field != "level" {
+		t.Errorf("errors.As FieldError = %v", fe)
+	}
+}
+
+func TestValidateSurfacesFromNew(t *testing.T) {
+	_, err := New(
+		WithFile(false, "", ""),
+		WithConsole(false, false),
+		WithOutputs(OutputConfig{Type: "pigeon"}, OutputConfig{Type: OutputStderr, Encoding: "xml"}),
+	)
+	want := []string{"outputs[0].type", "outputs[1].encoding"}
+	if got := validationFields(t, err); !reflect.DeepEqual(got, want) {
+		t.Errorf("fields = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,304 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// WebhookConfig webhook 通知配置，未设置的字段使用默认值
+type WebhookConfig struct {
+	URL          string            // 接收通知的地址，必填
+	Headers      map[string]string // 附加的请求头，如 Authorization
+	BatchSize    int               // 每次请求最多的日志条数，默认 100
+	Debounce     time.Duration     // 收到日志后等待的静默时间，期间的新日志合并为一批，默认 2s
+	MaxWait      time.Duration     // 一批日志从第一条起最长等待时间，默认 10s
+	MaxPending   int               // 等待发送的日志上限，超出时丢弃新日志，默认 1000
+	MaxRetries   int               // 请求失败后的重试次数，默认 3，为负数时不重试
+	RetryBackoff time.Duration     // 首次重试前的等待时间，之后每次加倍，默认 1s
+	Timeout      time.Duration     // 单次请求超时，默认 5s
+	Client       *http.Client      // 默认 http.DefaultClient
+	OnError      func(error)       // 一批日志最终发送失败时调用，默认写到标准错误
+}
+
+// WebhookEntry 通知中的一条日志
+type WebhookEntry struct {
+	Time    time.Time       `json:"time"`
+	Level   string          `json:"level"`
+	Logger  string          `json:"logger,omitempty"`
+	Message string          `json:"message"`
+	Caller  string          `json:"caller,omitempty"`
+	Stack   string          `json:"stack,omitempty"`
+	Fields  json.RawMessage `json:"fields,omitempty"`
+}
+
+// WebhookPayload 每次请求的 JSON 请求体
+type WebhookPayload struct {
+	Entries []WebhookEntry `json:"entries"`
+	Dropped uint64         `json:"dropped,omitempty"` // 上次请求以来因等待队列已满而丢弃的条数
+}
+
+// Webhook 将日志合并成批，以 JSON POST 到指定地址，失败时按指数退避重试。
+// 通常与 OnEntry 一起使用：
+//
+//	wh, err := logger.NewWebhook(logger.WebhookConfig{URL: "https://example.com/hook"})
+//	cancel := l.OnEntry(zapcore.ErrorLevel, wh.Notify)
+//	defer wh.Close() // 在 cancel 或 logger.Close 之后调用，以发送剩余日志
+type Webhook struct {
+	cfg    WebhookConfig
+	client *http.Client
+
+	mu      sync.Mutex
+	pending []WebhookEntry
+	dropped uint64
+
+	notify chan struct{}
+	done   chan struct{}
+	exited chan struct{}
+	once   sync.Once
+}
+
+// NewWebhook 创建 webhook 通知并启动后台发送协程
+func NewWebhook(cfg WebhookConfig) (*Webhook, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid webhook url %q: expected http or https", cfg.URL)
+	}
+
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.Debounce <= 0 {
+		cfg.Debounce = 2 * time.Second
+	}
+	if cfg.MaxWait <= 0 {
+		cfg.MaxWait = 10 * time.Second
+	}
+	if cfg.MaxPending <= 0 {
+		cfg.MaxPending = 1000
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.OnError == nil {
+		cfg.OnError = func(err error) {
+			fmt.Fprintf(os.Stderr, "[logger] webhook: %v\n", err)
+		}
+	}
+
+	w := &Webhook{
+		cfg:    cfg,
+		client: cfg.Client,
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+	if w.client == nil {
+		w.client = http.DefaultClient
+	}
+	go w.run()
+	return w, nil
+}
+
+// Notify 将一条日志加入待发送队列，签名与 OnEntry 的回调一致
+func (w *Webhook) Notify(ent zapcore.Entry, fields []zapcore.Field) {
+	entry := WebhookEntry{
+		Time:    ent.Time,
+		Level:   ent.Level.String(),
+		Logger:  ent.LoggerName,
+		Message: ent.Message,
+		Stack:   ent.Stack,
+		Fields:  encodeWebhookFields(fields),
+	}
+	if ent.Caller.Defined {
+		entry.Caller = ent.Caller.TrimmedPath()
+	}
+
+	w.mu.Lock()
+	if len(w.pending) >= w.cfg.MaxPending {
+		w.dropped++
+		w.mu.Unlock()
+		return
+	}
+	w.pending = append(w.pending, entry)
+	w.mu.Unlock()
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+// encodeWebhookFields 将字段编码为 JSON 对象
+func encodeWebhookFields(fields []zapcore.Field) json.RawMessage {
+	if len(fields) == 0 {
+		return nil
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	data, err := json.Marshal(enc.Fields)
+	if err != nil {
+		data, _ = json.Marshal(map[string]string{"fields_error": err.Error()})
+	}
+	return data
+}
+
+// Close 停止后台协程，剩余日志各尝试发送一次，不再重试
+func (w *Webhook) Close() error {
+	w.once.Do(func() {
+		close(w.done)
+	})
+	<-w.exited
+	return nil
+}
+
+func (w *Webhook) run() {
+	defer close(w.exited)
+
+	for {
+		select {
+		case <-w.notify:
+		case <-w.done:
+			w.flush()
+			return
+		}
+
+		// 等待静默 Debounce，或累计满一批，或等满 MaxWait
+		debounce := time.NewTimer(w.cfg.Debounce)
+		deadline := time.NewTimer(w.cfg.MaxWait)
+	wait:
+		for !w.full() {
+			select {
+			case <-w.notify:
+				debounce.Reset(w.cfg.Debounce)
+			case <-debounce.C:
+				break wait
+			case <-deadline.C:
+				break wait
+			case <-w.done:
+				break wait
+			}
+		}
+		debounce.Stop()
+		deadline.Stop()
+
+		w.flush()
+	}
+}
+
+// full 判断待发送的日志是否已满一批
+func (w *Webhook) full() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.pending) >= w.cfg.BatchSize
+}
+
+// take 取出一批待发送的日志
+func (w *Webhook) take() *WebhookPayload {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.pending) == 0 {
+		return nil
+	}
+	n := min(len(w.pending), w.cfg.BatchSize)
+	payload := &WebhookPayload{
+		Entries: w.pending[:n:n],
+		Dropped: w.dropped,
+	}
+	w.pending = w.pending[n:]
+	if len(w.pending) == 0 {
+		w.pending = nil
+	}
+	w.dropped = 0
+	return payload
+}
+
+// flush 分批发送全部待发送的日志
+func (w *Webhook) flush() {
+	for {
+		payload := w.take()
+		if payload == nil {
+			return
+		}
+		if err := w.send(payload); err != nil {
+			w.cfg.OnError(err)
+		}
+	}
+}
+
+// send 发送一批日志，失败时按指数退避重试，Close 之后不再重试
+func (w *Webhook) send(payload *WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	backoff := w.cfg.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := w.post(body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= w.cfg.MaxRetries {
+			return fmt.Errorf("failed to send %d entries after %d attempts: %w", len(payload.Entries), attempt+1, err)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-w.done:
+			timer.Stop()
+			return fmt.Errorf("failed to send %d entries before close: %w", len(payload.Entries), err)
+		}
+		backoff *= 2
+	}
+}
+
+// post 发送一次请求，返回失败时是否值得重试
+func (w *Webhook) post(body []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("unexpected status %s", resp.Status)
+}
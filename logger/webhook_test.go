@@ -0,0 +1,232 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// webhookServer 记录收到的请求，按 statuses 依次返回状态码，用完后返回 200
+type webhookServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	payloads []WebhookPayload
+	times    []time.Time
+	headers  []http.Header
+	attempts int
+}
+
+func newWebhookServer(t *testing.T, statuses ...int) *webhookServer {
+	t.Helper()
+	s := &webhookServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.attempts++
+		if len(s.statuses) > 0 {
+			status := s.statuses[0]
+			s.statuses = s.statuses[1:]
+			w.WriteHeader(status)
+			return
+		}
+
+		var p WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+		s.payloads = append(s.payloads, p)
+		s.times = append(s.times, time.Now())
+		s.headers = append(s.headers, r.Header.Clone())
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// batchSizes 返回各请求的日志条数
+func (s *webhookServer) batchSizes() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sizes := make([]int, len(s.payloads))
+	for i, p := range s.payloads {
+		sizes[i] = len(p.Entries)
+	}
+	return sizes
+}
+
+// waitRequests 等待收到 n 个成功的请求
+func (s *webhookServer) waitRequests(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		got := len(s.payloads)
+		s.mu.Unlock()
+		if got >= n {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d webhook requests", n)
+}
+
+func notifyEntry(wh *Webhook, msg string, fields ...zapcore.Field) {
+	wh.Notify(zapcore.Entry{Level: zapcore.ErrorLevel, Time: time.Now(), Message: msg}, fields)
+}
+
+func TestWebhookBatching(t *testing.T) {
+	srv := newWebhookServer(t)
+	wh, err := NewWebhook(WebhookConfig{
+		URL:       srv.URL,
+		Headers:   map[string]string{"Authorization": "Bearer token"},
+		BatchSize: 3,
+		Debounce:  50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewWebhook: %v", err)
+	}
+
+	for i := 0; i < 7; i++ {
+		notifyEntry(wh, "failed", zap.Int("n", i))
+	}
+	_ = wh.Close()
+
+	// 满一批即发送，不等待静默期；发送期间到达的日志组成后续批次
+	sizes := srv.batchSizes()
+	total := 0
+	for _, n := range sizes {
+		if n > 3 {
+			t.Errorf("batch of %d entries exceeds BatchSize", n)
+		}
+		total += n
+	}
+	if total != 7 || len(sizes) < 3 || sizes[0] != 3 {
+		t.Errorf("batch sizes = %v, want 7 entries in batches of at most 3", sizes)
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	h := srv.headers[0]
+	if h.Get("Content-Type") != "application/json" || h.Get("Authorization") != "Bearer token" {
+		t.Errorf("headers = %v", h)
+	}
+	e := srv.payloads[0].Entries[0]
+	if e.Level != "error" || e.Message != "failed" || string(e.Fields) != `{"n":0}` {
+		t.Errorf("entry = %+v", e)
+	}
+}
+
+func TestWebhookDebounce(t *testing.T) {
+	srv := newWebhookServer(t)
+	wh, err := NewWebhook(WebhookConfig{
+		URL:      srv.URL,
+		Debounce: 100 * time.Millisecond,
+		MaxWait:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewWebhook: %v", err)
+	}
+	defer wh.Close()
+
+	// 静默期内的日志合并为一批，静默期从最后一条起算
+	notifyEntry(wh, "first")
+	time.Sleep(40 * time.Millisecond)
+	notifyEntry(wh, "second")
+	last := time.Now()
+	srv.waitRequests(t, 1)
+
+	if sizes := srv.batchSizes(); len(sizes) != 1 || sizes[0] != 2 {
+		t.Errorf("batch sizes = %v, want [2]", sizes)
+	}
+	srv.mu.Lock()
+	elapsed := srv.times[0].Sub(last)
+	srv.mu.Unlock()
+	if elapsed < 100*time.Millisecond {
+		t.Errorf("sent %v after the last entry, want at least the debounce interval", elapsed)
+	}
+}
+
+func TestWebhookMaxWait(t *testing.T) {
+	srv := newWebhookServer(t)
+	wh, err := NewWebhook(WebhookConfig{
+		URL:      srv.URL,
+		Debounce: 5 * time.Second,
+		MaxWait:  50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewWebhook: %v", err)
+	}
+	defer wh.Close()
+
+	start := time.Now()
+	notifyEntry(wh, "first")
+	srv.waitRequests(t, 1)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("sent after %v, want about MaxWait", elapsed)
+	}
+}
+
+func TestWebhookRetry(t *testing.T) {
+	srv := newWebhookServer(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	var errs []error
+	wh, err := NewWebhook(WebhookConfig{
+		URL:          srv.URL,
+		Debounce:     time.Millisecond,
+		RetryBackoff: 10 * time.Millisecond,
+		OnError:      func(err error) { errs = append(errs, err) },
+	})
+	if err != nil {
+		t.Fatalf("NewWebhook: %v", err)
+	}
+
+	notifyEntry(wh, "failed")
+	srv.waitRequests(t, 1)
+	_ = wh.Close()
+
+	srv.mu.Lock()
+	attempts := srv.attempts
+	srv.mu.Unlock()
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3 (503, 429, 200)", attempts)
+	}
+	if len(errs) != 0 {
+		t.Errorf("OnError called: %v", errs)
+	}
+}
+
+func TestWebhookNoRetryOnClientError(t *testing.T) {
+	srv := newWebhookServer(t, http.StatusBadRequest)
+	errs := make(chan error, 1)
+	wh, err := NewWebhook(WebhookConfig{
+		URL:          srv.URL,
+		Debounce:     time.Millisecond,
+		RetryBackoff: 10 * time.Millisecond,
+		OnError:      func(err error) { errs <- err },
+	})
+	if err != nil {
+		t.Fatalf("NewWebhook: %v", err)
+	}
+	defer wh.Close()
+
+	notifyEntry(wh, "failed")
+	select {
+	case <-errs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnError not called")
+	}
+
+	srv.mu.Lock()
+	attempts := srv.attempts
+	srv.mu.Unlock()
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}